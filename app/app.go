@@ -4,7 +4,6 @@ package app
 import (
 	"encoding/json"
 	"flag"
-	"fmt"
 	"github.com/neurlang/goruut/helpers/log"
 	"os"
 	"regexp"
@@ -29,13 +28,14 @@ func NewApp() *App {
 	return app
 }
 
-// LoadCmdArgs loads command-line arguments for the application.
-func (app *App) LoadCmdArgs() *Args {
+// LoadCmdArgs loads command-line arguments for the application. The flags of a command
+// are registered on the flag set before, and are parsed together.
+func (app *App) LoadCmdArgs(flags *flag.FlagSet, args []string) *Args {
 	app.args = &Args{}
 
-	flag.Var(&app.args.ConfigFiles, "configfile", "Sets the config file")
-	flag.Var(&app.args.ConfigDirs, "configdir", "Sets the config dir")
-	flag.Parse()
+	flags.Var(&app.args.ConfigFiles, "configfile", "Sets the config file")
+	flags.Var(&app.args.ConfigDirs, "configdir", "Sets the config dir")
+	log.Error0(flags.Parse(args))
 
 	return app.args
 }
@@ -64,8 +64,7 @@ func (app *App) LoadConfigs(_ *DependencyInjection) *Configs {
 
 		b, err := os.ReadFile(filename)
 		if err != nil {
-			fmt.Print(err)
-			continue
+			log.Field("config", filename).Fatalf("Couldn't read config: %v", err)
 		}
 
 		// do env vars substitution from the environment
@@ -81,8 +80,7 @@ func (app *App) LoadConfigs(_ *DependencyInjection) *Configs {
 		var conf models.AppConfig
 		err = json.Unmarshal(b, &conf)
 		if err != nil {
-			fmt.Print(err)
-			continue
+			log.Field("config", filename).Fatalf("Couldn't parse config: %v", err)
 		}
 
 		confs.Configs = append(confs.Configs, conf)
@@ -90,6 +88,11 @@ func (app *App) LoadConfigs(_ *DependencyInjection) *Configs {
 		log.Field("config", filename).Infof("Loaded config")
 	}
 
+	// a typo must not start the server on other ports and limits than configured
+	if err := confs.Validate(); err != nil {
+		log.Field("configs", app.args.ConfigFiles).Fatalf("Invalid config: %v", err)
+	}
+
 	return &confs
 }
 
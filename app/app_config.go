@@ -1,5 +1,12 @@
 package app
 
+import "github.com/neurlang/goruut/pkg/ipaflavor"
+import "github.com/neurlang/goruut/pkg/ranking"
+import "github.com/neurlang/goruut/pkg/registry"
+import "github.com/neurlang/goruut/pkg/symboltable"
+import "github.com/neurlang/goruut/repo/models"
+import "time"
+
 // GetHttpPort retrieves the HTTP port from the dataset downloads.
 func (ac *Configs) GetHttpPort() string {
 	for _, config := range ac.Configs {
@@ -48,6 +55,66 @@ func (ac *Configs) GetIpaFlavors() map[string]map[string]string {
 	return nil
 }
 
+// GetIpaFlavorRules retrieves the contextual ipa flavor rules from the configurations.
+func (ac *Configs) GetIpaFlavorRules() map[string][]ipaflavor.Rule {
+	for _, config := range ac.Configs {
+		site := config.GetIpaFlavorRules()
+
+		if site != nil {
+			return site
+		}
+	}
+	return nil
+}
+
+// GetIpaPhoneClasses retrieves the ipa phone classes from the configurations.
+func (ac *Configs) GetIpaPhoneClasses() map[string][]string {
+	for _, config := range ac.Configs {
+		site := config.GetIpaPhoneClasses()
+
+		if site != nil {
+			return site
+		}
+	}
+	return nil
+}
+
+// GetDefaultIpaFlavors retrieves the per language default ipa flavors from the configurations.
+func (ac *Configs) GetDefaultIpaFlavors() map[string][]string {
+	for _, config := range ac.Configs {
+		site := config.GetDefaultIpaFlavors()
+
+		if site != nil {
+			return site
+		}
+	}
+	return nil
+}
+
+// GetSymbolTables retrieves the voice model symbol tables from the configurations.
+func (ac *Configs) GetSymbolTables() map[string]*symboltable.Table {
+	for _, config := range ac.Configs {
+		site := config.GetSymbolTables()
+
+		if site != nil {
+			return site
+		}
+	}
+	return nil
+}
+
+// GetSelectionPolicy retrieves the ranking of pronunciation candidates from the configurations.
+func (ac *Configs) GetSelectionPolicy() *ranking.Policy {
+	for _, config := range ac.Configs {
+		site := config.GetSelectionPolicy()
+
+		if site != nil {
+			return site
+		}
+	}
+	return nil
+}
+
 // GetPolicyMaxWords retrieves the max words per request count policy from the configurations.
 func (ac *Configs) GetPolicyMaxWords() int {
 	for _, config := range ac.Configs {
@@ -60,6 +127,89 @@ func (ac *Configs) GetPolicyMaxWords() int {
 	return 0
 }
 
+// GetMemoryBudget retrieves the bound of the memory of the loaded languages from the configurations.
+func (ac *Configs) GetMemoryBudget() int64 {
+	for _, config := range ac.Configs {
+		site := config.GetMemoryBudget()
+
+		if site != 0 {
+			return site
+		}
+	}
+	return 0
+}
+
+// GetPinnedLanguages retrieves the languages which are never unloaded from the configurations,
+// by their goruut names.
+func (ac *Configs) GetPinnedLanguages() (ret []string) {
+	for _, config := range ac.Configs {
+		for _, lang := range config.GetPinnedLanguages() {
+			ret = append(ret, registry.Canonical(lang))
+		}
+	}
+	return
+}
+
+// GetWatchModels retrieves how often the loaded models are checked for changes from the configurations.
+func (ac *Configs) GetWatchModels() time.Duration {
+	for _, config := range ac.Configs {
+		site := config.GetWatchModels()
+
+		if site != 0 {
+			return site
+		}
+	}
+	return 0
+}
+
+// GetWordCacheSize retrieves how many words phonemized by the model are cached from the configurations.
+func (ac *Configs) GetWordCacheSize() int {
+	for _, config := range ac.Configs {
+		site := config.GetWordCacheSize()
+
+		if site != 0 {
+			return site
+		}
+	}
+	return 0
+}
+
+// GetWordCacheTTL retrieves how long a cached word is kept from the configurations.
+func (ac *Configs) GetWordCacheTTL() time.Duration {
+	for _, config := range ac.Configs {
+		site := config.GetWordCacheTTL()
+
+		if site != 0 {
+			return site
+		}
+	}
+	return 0
+}
+
+// GetWordCacheFile retrieves the file keeping the cached words across restarts from the configurations.
+func (ac *Configs) GetWordCacheFile() string {
+	for _, config := range ac.Configs {
+		site := config.GetWordCacheFile()
+
+		if site != "" {
+			return site
+		}
+	}
+	return ""
+}
+
+// GetWordCacheFileLimit retrieves the bound of the word cache file from the configurations.
+func (ac *Configs) GetWordCacheFileLimit() int64 {
+	for _, config := range ac.Configs {
+		site := config.GetWordCacheFileLimit()
+
+		if site != 0 {
+			return site
+		}
+	}
+	return 0
+}
+
 // GetLoadModels retrieves the models to be loaded from the configurations.
 func (ac *Configs) GetLoadModels() []*struct {
 	Lang string
@@ -75,3 +225,30 @@ func (ac *Configs) GetLoadModels() []*struct {
 	}
 	return nil
 }
+
+// Validate checks the configurations as they are merged, the ipa flavor rules of one
+// configuration may refer to the phone classes of another.
+func (ac *Configs) Validate() error {
+	var merged = models.AppConfig{
+		IpaFlavors:      ac.GetIpaFlavors(),
+		IpaFlavorRules:  ac.GetIpaFlavorRules(),
+		IpaPhoneClasses: ac.GetIpaPhoneClasses(),
+		SymbolTables:    ac.GetSymbolTables(),
+		SelectionPolicy: ac.GetSelectionPolicy(),
+	}
+	for _, config := range ac.Configs {
+		if merged.WatchModels == "" {
+			merged.WatchModels = config.WatchModels
+		}
+		if merged.MemoryBudgetMB == 0 {
+			merged.MemoryBudgetMB = config.MemoryBudgetMB
+		}
+		if merged.WordCacheTTL == "" {
+			merged.WordCacheTTL = config.WordCacheTTL
+		}
+		if merged.WordCacheFileMB == 0 {
+			merged.WordCacheFileMB = config.WordCacheFileMB
+		}
+	}
+	return merged.Validate()
+}
@@ -90,11 +90,10 @@ type DictGetter struct {
 }
 
 func (d *DictGetter) GetDict(lang, filename string) ([]byte, error) {
-	if d.dumpwrong && (filename == "missing.all.zlib" ||
-		filename == "weights3.json.zlib" ||
+	if d.dumpwrong && (filename == "weights3.json.zlib" ||
 		filename == "weights3_reverse.json.zlib") {
 		println("intentional error:")
-		return nil, fmt.Errorf("generating missing all zlib intentional error")
+		return nil, fmt.Errorf("skipping old weights intentional error")
 	}
 	if lang == d.coolname && strings.HasSuffix(d.modelfile, filename) {
 		data, err := os.ReadFile(d.modelfile)
@@ -257,6 +256,10 @@ again:
 		di.Add((interfaces.IpaFlavor)(dummy{}))
 		di.Add((interfaces.PolicyMaxWords)(dummy{}))
 		p = lib.NewPhonemizer(di)
+		if dumpwrong != nil && *dumpwrong {
+			// dump what the model alone gets wrong
+			p.Disable(lib.PipelineStages{Lexicon: true, Cache: true})
+		}
 	}
 
 	var percent, errsum, total atomic.Uint64
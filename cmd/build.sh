@@ -10,5 +10,7 @@ go build -o ./dicttomap/dicttomap ./dicttomap
 go build -o ./goruut/goruut ./goruut
 go build -o ./phondephontest/phondephontest ./phondephontest
 go build -o ./homotest/homotest ./homotest
+go build -o ./dictsize/dictsize ./dictsize
+go build -o ./manifest/manifest ./manifest
 
 echo "Goruut binaries built successfuly"
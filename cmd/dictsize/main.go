@@ -0,0 +1,97 @@
+// Command dictsize reports the bytes each language embeds into the binary, to help
+// choose the languages of a build with the goruut_subset tag. Build it with the same
+// tags as the binary to see what that binary carries.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/neurlang/goruut/dicts"
+	"github.com/neurlang/goruut/pkg/registry"
+)
+
+// Size is the embedded bytes of one language.
+type Size struct {
+	Name     string
+	Dir      string
+	BuildTag string
+	// Lexicon counts the missing* and lexicon* files, the words learned by heart.
+	Lexicon int64
+	// Model counts the weights* files.
+	Model int64
+	// Config counts the language*.json files.
+	Config int64
+	Total  int64
+}
+
+func measure(l registry.Language) (size Size, err error) {
+	size.Name, size.Dir, size.BuildTag = l.Name, l.Dir, dicts.BuildTag(l.Name)
+	err = fs.WalkDir(l.FS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(d.Name(), "missing"), strings.HasPrefix(d.Name(), "lexicon"):
+			size.Lexicon += info.Size()
+		case strings.HasPrefix(d.Name(), "weights"):
+			size.Model += info.Size()
+		default:
+			size.Config += info.Size()
+		}
+		size.Total += info.Size()
+		return nil
+	})
+	return
+}
+
+func mib(n int64) string {
+	return fmt.Sprintf("%.2f", float64(n)/(1<<20))
+}
+
+func main() {
+	asJson := flag.Bool("json", false, "print the sizes as json")
+	byName := flag.Bool("byname", false, "sort by name instead of the total size")
+	flag.Parse()
+
+	var sizes []Size
+	var total int64
+	for _, l := range registry.All() {
+		size, err := measure(l)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR:", l.Name, err)
+			os.Exit(1)
+		}
+		sizes = append(sizes, size)
+		total += size.Total
+	}
+	if !*byName {
+		sort.SliceStable(sizes, func(i, j int) bool {
+			return sizes[i].Total > sizes[j].Total
+		})
+	}
+	if *asJson {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(sizes); err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Printf("%-24s %-36s %10s %10s %10s %10s\n", "LANGUAGE", "BUILD TAG", "LEXICON", "MODEL", "CONFIG", "TOTAL MiB")
+	for _, size := range sizes {
+		fmt.Printf("%-24s %-36s %10s %10s %10s %10s\n", size.Name, size.BuildTag,
+			mib(size.Lexicon), mib(size.Model), mib(size.Config), mib(size.Total))
+	}
+	fmt.Printf("%d languages, %s MiB\n", len(sizes), mib(total))
+}
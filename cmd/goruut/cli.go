@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/neurlang/goruut/lib"
+	"github.com/neurlang/goruut/models/requests"
+	"github.com/neurlang/goruut/models/responses"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"github.com/neurlang/goruut/repo/models"
+	"github.com/sirupsen/logrus"
+)
+import application "github.com/neurlang/goruut/app"
+
+// phonemize runs the phonemize or the dephonemize command and returns the exit code.
+// Every line of the input is a sentence, the output keeps the lines.
+func phonemize(command string, args []string) int {
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	lang := flags.String("lang", "", "Sets the language, such as English or en")
+	langs := flags.String("langs", "", "Sets the fallback languages, separated by commas")
+	flavor := flags.String("flavor", "", "Sets the IPA flavors of the config, separated by commas")
+	split := flags.Bool("split-sentences", false, "Splits the lines into sentences, writing a line per sentence")
+	reverse := flags.Bool("reverse", false, "Runs the other direction")
+	format := flags.String("format", "ipa", "Sets the output: ipa, tsv (word and ipa) or jsonl")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: goruut %s [flags] [file ...]\n\nReads standard input without files.\n\n", command)
+		flags.PrintDefaults()
+	}
+
+	// the logs go to the standard error, only the warnings unless the config says otherwise
+	logrus.SetLevel(logrus.WarnLevel)
+
+	var app = application.NewApp()
+
+	var di, conf = newDependencies(app, app.LoadCmdArgs(flags, args))
+
+	// the word limit protects the server only, the first config lifts it
+	conf.Configs = append([]models.AppConfig{{PolicyMaxWords: math.MaxInt32}}, conf.Configs...)
+
+	di.Add((interfaces.PolicyMaxWords)(conf))
+
+	var write func(w *bufio.Writer, resp responses.PhonemizeSentence) error
+	switch *format {
+	case "ipa":
+		write = writeIpa
+	case "tsv":
+		write = writeTsv
+	case "jsonl":
+		write = writeJsonl
+	default:
+		fmt.Fprintf(os.Stderr, "goruut %s: unknown format %q\n", command, *format)
+		return 2
+	}
+	if *lang == "" && *langs == "" {
+		fmt.Fprintf(os.Stderr, "goruut %s: -lang is required\n", command)
+		return 2
+	}
+
+	var request = requests.PhonemizeSentence{
+		Language:       *lang,
+		Languages:      splitList(*langs),
+		IpaFlavors:     splitList(*flavor),
+		IsReverse:      (command == "dephonemize") != *reverse,
+		SplitSentences: *split,
+	}
+
+	var p = lib.NewPhonemizer(di)
+	var out = bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	var inputs = flags.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	for _, input := range inputs {
+		var reader io.Reader = os.Stdin
+		if input != "-" {
+			file, err := os.Open(input)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "goruut %s: %v\n", command, err)
+				return 1
+			}
+			defer file.Close()
+			reader = file
+		}
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(nil, 16<<20)
+		for scanner.Scan() {
+			request.Sentence = scanner.Text()
+			resp := p.Sentence(request)
+			if resp.ErrorUnsupportedLanguage != "" {
+				fmt.Fprintf(os.Stderr, "goruut %s: %s\n", command, resp.ErrorUnsupportedLanguage)
+				return 1
+			}
+			if err := write(out, resp); err != nil {
+				fmt.Fprintf(os.Stderr, "goruut %s: %v\n", command, err)
+				return 1
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "goruut %s: %s: %v\n", command, input, err)
+			return 1
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "goruut %s: %v\n", command, err)
+		return 1
+	}
+	return 0
+}
+
+// splitList splits a list separated by commas, nil when empty.
+func splitList(list string) (ret []string) {
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+// writeIpa writes the words with their punctuation separated by spaces, a line per
+// sentence.
+func writeIpa(w *bufio.Writer, resp responses.PhonemizeSentence) error {
+	var words []string
+	for _, word := range resp.Words {
+		words = append(words, word.PrePunct+word.Phonetic+word.PostPunct)
+		if word.IsLast {
+			w.WriteString(strings.Join(words, " ") + "\n")
+			words = nil
+		}
+	}
+	if len(resp.Words) == 0 || words != nil {
+		w.WriteString(strings.Join(words, " ") + "\n")
+	}
+	return w.Flush()
+}
+
+// writeTsv writes a line per word, the word and its pronunciation separated by a tab.
+func writeTsv(w *bufio.Writer, resp responses.PhonemizeSentence) error {
+	for _, word := range resp.Words {
+		w.WriteString(word.CleanWord + "\t" + word.Phonetic + "\n")
+	}
+	return w.Flush()
+}
+
+// writeJsonl writes the response as a line of JSON.
+func writeJsonl(w *bufio.Writer, resp responses.PhonemizeSentence) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	w.Write(append(data, '\n'))
+	return w.Flush()
+}
@@ -2,34 +2,54 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/martinarisk/di/dependency_injection"
+	"os"
+	"strings"
 )
 import application "github.com/neurlang/goruut/app"
 import "github.com/neurlang/goruut/dicts"
 import "github.com/neurlang/goruut/loader"
+import "github.com/neurlang/goruut/pkg/residency"
 import "github.com/neurlang/goruut/repo/interfaces"
 
+const usage = `Usage:
+  goruut serve [-configfile file] [-configdir dir]
+  goruut phonemize [flags] [file ...]
+  goruut dephonemize [flags] [file ...]
+
+Without a command, goruut serves. Run a command with -h for its flags.
+`
+
 // main is the main function for the application executable
 func main() {
 
-	var di = dependency_injection.NewDependencyInjection()
+	var command, args = "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve(args)
+	case "phonemize", "dephonemize":
+		os.Exit(phonemize(command, args))
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "goruut: unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
 
-	di.Add((interfaces.DictGetter)(dicts.DictGetter{}))
+// serve runs the HTTP server until it is killed.
+func serve(args []string) {
 
 	var app = application.NewApp()
 
-	di.Add(app.LoadCmdArgs())
+	var di, conf = newDependencies(app, app.LoadCmdArgs(flag.NewFlagSet("serve", flag.ExitOnError), args))
 
-	var conf = app.LoadConfigs(di)
-
-	conf.ConfigureLogger()
-
-	di.Add((interfaces.LoadModels)(conf))
-
-	var loader = loader.NewLoader(di)
-
-	di.Add((interfaces.DictGetter)(loader))
-	di.Add((interfaces.IpaFlavor)(conf))
 	di.Add((interfaces.PolicyMaxWords)(conf))
 
 	di.Add(conf)
@@ -44,3 +64,40 @@ func main() {
 
 	server.RunForever()
 }
+
+// newDependencies loads the configs and adds the dependencies which the server shares
+// with the command-line phonemization.
+func newDependencies(app *application.App, args *application.Args) (*dependency_injection.DependencyInjection, *application.Configs) {
+
+	var di = dependency_injection.NewDependencyInjection()
+
+	di.Add((interfaces.DictGetter)(dicts.DictGetter{}))
+
+	di.Add(args)
+
+	var conf = app.LoadConfigs(di)
+
+	conf.ConfigureLogger()
+
+	di.Add((interfaces.LoadModels)(conf))
+	di.Add((interfaces.WatchModels)(conf))
+
+	var loader = loader.NewLoader(di)
+
+	di.Add((interfaces.DictGetter)(loader))
+	di.Add((interfaces.ReloadNotifier)(loader))
+
+	var resident = residency.New(conf.GetMemoryBudget(), conf.GetPinnedLanguages()...)
+
+	loader.OnReload(resident.Forget)
+
+	di.Add((interfaces.Residency)(resident))
+	di.Add((interfaces.WordCache)(conf))
+	di.Add((interfaces.IpaFlavor)(conf))
+	di.Add((interfaces.IpaFlavorRules)(conf))
+	di.Add((interfaces.DefaultIpaFlavors)(conf))
+	di.Add((interfaces.SymbolTables)(conf))
+	di.Add((interfaces.SelectionPolicy)(conf))
+
+	return di, conf
+}
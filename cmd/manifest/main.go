@@ -0,0 +1,220 @@
+// Command manifest creates, inspects and verifies the manifest.json of a model package,
+// a language directory under dicts or a zip file given to the loader.
+//
+//	manifest create [flags] <dir>
+//	manifest inspect <dir|zip>
+//	manifest verify <dir|zip>
+//
+// Create keeps the description of the models whose weights did not change, so run it
+// again after editing the lexicon and after each training.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/neurlang/goruut/pkg/manifest"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: manifest create [flags] <dir>")
+	fmt.Fprintln(os.Stderr, "       manifest inspect <dir|zip>")
+	fmt.Fprintln(os.Stderr, "       manifest verify <dir|zip>")
+	os.Exit(2)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "ERROR:", err)
+	os.Exit(1)
+}
+
+// open returns the files of the package, a directory or a zip file.
+func open(name string) (fs.FS, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return os.DirFS(name), nil
+	}
+	return zip.OpenReader(name)
+}
+
+func read(name string) (*manifest.Manifest, fs.FS) {
+	fsys, err := open(name)
+	if err != nil {
+		fatal(err)
+	}
+	data, err := fs.ReadFile(fsys, manifest.FileName)
+	if err != nil {
+		fatal(err)
+	}
+	m, err := manifest.Parse(data)
+	if err != nil {
+		fatal(err)
+	}
+	return m, fsys
+}
+
+var languageName = regexp.MustCompile(`Name:\s*"([^"]+)"`)
+
+// language returns the goruut name registered by the language.go of the directory.
+func language(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "language.go"))
+	if err != nil {
+		return ""
+	}
+	if match := languageName.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// lexicon returns the checksum of the lexicon file, decompressed when it is zlib, so that
+// compressing it again does not change the checksum.
+func lexicon(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(name, ".zlib") {
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(reader); err != nil {
+			return "", err
+		}
+	}
+	return manifest.Sum(data), nil
+}
+
+func create(args []string) {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	lang := flags.String("language", "", "goruut name of the language, read from language.go by default")
+	models := flags.String("models", "weights6*.json.zlib,weights7*.json.zlib", "comma separated patterns of the model weights to describe")
+	trained := flags.String("trained", "", "training date of the changed models, the modification time of the weights by default")
+	lexiconFile := flags.String("lexicon", "missing.all.zlib", "lexicon the changed models learned, relative to the directory")
+	arch := manifest.Default
+	flags.StringVar(&arch.Name, "network", arch.Name, "network of the changed models")
+	flags.IntVar(&arch.Fanout1, "fanout1", arch.Fanout1, "width of the layers of the changed models")
+	flags.IntVar(&arch.Fanout2, "fanout2", arch.Fanout2, "width multiplier of the changed models")
+	flags.IntVar(&arch.Fanout3, "fanout3", arch.Fanout3, "attention blocks of the changed models")
+	flags.IntVar(&arch.Fanout4, "fanout4", arch.Fanout4, "bits of the first sochastic layer of the changed models")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	dir := flags.Arg(0)
+	if *lang == "" {
+		*lang = language(dir)
+	}
+	if err := arch.Validate(); err != nil {
+		fatal(err)
+	}
+
+	fsys := os.DirFS(dir)
+	m, err := manifest.Create(fsys, *lang)
+	if err != nil {
+		fatal(err)
+	}
+	var old *manifest.Manifest
+	if data, err := fs.ReadFile(fsys, manifest.FileName); err == nil {
+		if old, err = manifest.Parse(data); err != nil {
+			fmt.Fprintln(os.Stderr, "WARNING: replacing the unreadable manifest:", err)
+		}
+	}
+
+	var lexiconSum string
+	for name, sum := range m.Files {
+		if !describe(name, *models) {
+			continue
+		}
+		if m.Models == nil {
+			m.Models = make(map[string]manifest.Model)
+		}
+		if old != nil && old.Files[name] == sum {
+			if model, ok := old.Models[name]; ok {
+				m.Models[name] = model
+				continue
+			}
+		}
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			fatal(err)
+		}
+		if fi.Size() == 0 {
+			continue
+		}
+		if lexiconSum == "" && *lexiconFile != "" {
+			if lexiconSum, err = lexicon(filepath.Join(dir, *lexiconFile)); err != nil {
+				fatal(err)
+			}
+		}
+		model := manifest.Model{Architecture: arch, Trained: *trained, Lexicon: lexiconSum}
+		if model.Trained == "" {
+			model.Trained = fi.ModTime().UTC().Format("2006-01-02")
+		}
+		m.Models[name] = model
+	}
+
+	data, err := m.Marshal()
+	if err != nil {
+		fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifest.FileName), data, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+// describe reports whether the file matches one of the comma separated patterns.
+func describe(name, patterns string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		if ok, _ := path.Match(strings.TrimSpace(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "create":
+		create(os.Args[2:])
+	case "inspect":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		m, _ := read(os.Args[2])
+		data, err := m.Marshal()
+		if err != nil {
+			fatal(err)
+		}
+		os.Stdout.Write(data)
+	case "verify":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		m, fsys := read(os.Args[2])
+		if err := m.VerifyFS(fsys, false); err != nil {
+			fatal(err)
+		}
+		fmt.Println("OK", m.Language, len(m.Files), "files")
+	default:
+		usage()
+	}
+}
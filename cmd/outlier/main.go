@@ -10,12 +10,29 @@ import (
 
 	di "github.com/martinarisk/di/dependency_injection"
 	"github.com/neurlang/goruut/dicts"
+	"github.com/neurlang/goruut/pkg/segment"
 	"github.com/neurlang/goruut/repo/interfaces"
 	"github.com/neurlang/levenshtein"
 )
 
 type dummy struct{}
 
+// languageFile is the part of language.json the segmentation needs.
+type languageFile struct {
+	Map            map[string][]string `json:"Map"`
+	SrcMulti       []string            `json:"SrcMulti"`
+	SrcMultiSuffix []string            `json:"SrcMultiSuffix"`
+}
+
+// segmenter splits words the way the phonemizer does.
+func (l *languageFile) segmenter() *segment.Segmenter {
+	suffix := make(map[string]struct{})
+	for _, unit := range l.SrcMultiSuffix {
+		suffix[unit] = struct{}{}
+	}
+	return segment.New(segment.Multi(l.Map, l.SrcMulti), suffix)
+}
+
 func (dummy) GetIpaFlavors() map[string]map[string]string {
 	return make(map[string]map[string]string)
 }
@@ -54,9 +71,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	var langStruct struct {
-		Map map[string][]string `json:"Map"`
-	}
+	var langStruct languageFile
 	if err := json.Unmarshal(langData, &langStruct); err != nil {
 		fmt.Printf("Error parsing language.json: %v\n", err)
 		os.Exit(1)
@@ -69,9 +84,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	var langReverseStruct struct {
-		Map map[string][]string `json:"Map"`
-	}
+	var langReverseStruct languageFile
 	if err := json.Unmarshal(langReverseData, &langReverseStruct); err != nil {
 		fmt.Printf("Error parsing language_reverse.json: %v\n", err)
 		os.Exit(1)
@@ -79,6 +92,8 @@ func main() {
 
 	langMap := langStruct.Map
 	langReverseMap := langReverseStruct.Map
+	segmenter := langStruct.segmenter()
+	reverseSegmenter := langReverseStruct.segmenter()
 
 	// Load lexicon.tsv
 	lexiconPath := filepath.Join("dicts", *langFile, "lexicon.tsv")
@@ -102,12 +117,12 @@ func main() {
 	// Process each word-IPA pair
 	loop(lexiconData, 1000, func(word, ipa string) {
 		// Forward direction: word -> IPA
-		segments := splitWordLongestPrefix(word, langMap)
+		segments := segmenter.Slice([]rune(word))
 		inferredIPA := generateInferredIPA(segments, langMap, ipa)
 		editDistForward := calculateEditDistance(inferredIPA, ipa)
 
 		// Reverse direction: IPA -> word
-		ipaSegments := splitWordLongestPrefix(ipa, langReverseMap)
+		ipaSegments := reverseSegmenter.Slice([]rune(ipa))
 		inferredWord := generateInferredWord(ipaSegments, langReverseMap, word)
 		editDistReverse := calculateEditDistance(inferredWord, word)
 
@@ -124,38 +139,6 @@ func main() {
 	})
 }
 
-// splitWordLongestPrefix splits a word using longest prefix matching based on runes
-func splitWordLongestPrefix(word string, langMap map[string][]string) []string {
-	var segments []string
-	runes := []rune(word)
-	i := 0
-
-	for i < len(runes) {
-		longestMatch := ""
-		longestMatchLen := 0
-
-		// Find the longest prefix match by testing all possible lengths
-		for j := i + 1; j <= len(runes); j++ {
-			prefix := string(runes[i:j])
-			if _, exists := langMap[prefix]; exists {
-				longestMatch = prefix
-				longestMatchLen = j - i
-			}
-		}
-
-		if longestMatch != "" {
-			segments = append(segments, longestMatch)
-			i += longestMatchLen
-		} else {
-			// If no match found, take single rune
-			segments = append(segments, string(runes[i]))
-			i++
-		}
-	}
-
-	return segments
-}
-
 // generateInferredIPA generates IPA from segments using greedy approach
 // For each segment, pick the phoneme option that gives lowest edit distance so far
 // Skip segments that have no mapping in langMap
@@ -0,0 +1,42 @@
+package v0
+
+import (
+	. "github.com/neurlang/goruut/controllers"
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/usecases"
+	"net/http"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+func init() {
+	AllControllers["/languages"] = &LanguagesController{}
+}
+
+type LanguagesController struct {
+	uc usecases.ILanguagesUsecase
+}
+
+func (c *LanguagesController) BackendType() ControllerBackendType {
+	return MainController
+}
+
+func (c *LanguagesController) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+
+	if request.Method != "GET" && request.Method != "POST" {
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	res := c.uc.List()
+
+	w.WriteHeader(200)
+	log.Error0(helpers.Write(w, log.Error1(helpers.SerializeJson(res))))
+}
+
+func (c *LanguagesController) Init(di *DependencyInjection) {
+	usecase := MustNeed(di, usecases.NewLanguagesUsecase)
+	c.uc = &usecase
+	di.Add(c)
+}
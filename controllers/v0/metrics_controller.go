@@ -0,0 +1,72 @@
+package v0
+
+import (
+	"bytes"
+	"fmt"
+	. "github.com/neurlang/goruut/controllers"
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/usecases"
+	"net/http"
+	"sort"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+func init() {
+	AllControllers["/metrics"] = &MetricsController{}
+}
+
+type MetricsController struct {
+	uc usecases.IMetricsUsecase
+}
+
+func (c *MetricsController) BackendType() ControllerBackendType {
+	return AdminController
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (c *MetricsController) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+
+	if request.Method != "GET" {
+		w.WriteHeader(500)
+		return
+	}
+
+	res := c.uc.Metrics()
+
+	var tiers []string
+	for tier := range res.WordCache {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	var buf bytes.Buffer
+	for _, metric := range []struct {
+		name, kind, help string
+		value            func(tier string) int64
+	}{
+		{"goruut_word_cache_hits_total", "counter", "Words found in the word cache.",
+			func(tier string) int64 { return res.WordCache[tier].Hits }},
+		{"goruut_word_cache_misses_total", "counter", "Words not found in the word cache.",
+			func(tier string) int64 { return res.WordCache[tier].Misses }},
+		{"goruut_word_cache_words", "gauge", "Words in the word cache.",
+			func(tier string) int64 { return res.WordCache[tier].Words }},
+		{"goruut_word_cache_bytes", "gauge", "Size of the word cache file.",
+			func(tier string) int64 { return res.WordCache[tier].Bytes }},
+	} {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, tier := range tiers {
+			fmt.Fprintf(&buf, "%s{tier=%q} %d\n", metric.name, tier, metric.value(tier))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(200)
+	log.Error0(helpers.Write(w, buf.Bytes()))
+}
+
+func (c *MetricsController) Init(di *DependencyInjection) {
+	usecase := MustNeed(di, usecases.NewMetricsUsecase)
+	c.uc = &usecase
+	di.Add(c)
+}
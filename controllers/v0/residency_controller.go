@@ -0,0 +1,42 @@
+package v0
+
+import (
+	. "github.com/neurlang/goruut/controllers"
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/usecases"
+	"net/http"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+func init() {
+	AllControllers["/residency"] = &ResidencyController{}
+}
+
+type ResidencyController struct {
+	uc usecases.IResidencyUsecase
+}
+
+func (c *ResidencyController) BackendType() ControllerBackendType {
+	return AdminController
+}
+
+func (c *ResidencyController) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+
+	if request.Method != "GET" && request.Method != "POST" {
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	res := c.uc.Report()
+
+	w.WriteHeader(200)
+	log.Error0(helpers.Write(w, log.Error1(helpers.SerializeJson(res))))
+}
+
+func (c *ResidencyController) Init(di *DependencyInjection) {
+	usecase := MustNeed(di, usecases.NewResidencyUsecase)
+	c.uc = &usecase
+	di.Add(c)
+}
@@ -0,0 +1,52 @@
+package v0
+
+import (
+	"encoding/json"
+	. "github.com/neurlang/goruut/controllers"
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/models/requests"
+	"github.com/neurlang/goruut/usecases"
+	"net/http"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+func init() {
+	AllControllers["/symbols/table"] = &SymbolTableController{}
+}
+
+type SymbolTableController struct {
+	uc usecases.ISymbolTableUsecase
+}
+
+func (c *SymbolTableController) BackendType() ControllerBackendType {
+	return AdminController
+}
+
+func (c *SymbolTableController) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+
+	if request.Method != "POST" {
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	decoder := json.NewDecoder(request.Body)
+	var req requests.SymbolTable
+	err := decoder.Decode(&req)
+	if err != nil {
+		log.Error0(err)
+		w.WriteHeader(500)
+		return
+	}
+	res := c.uc.Store(req)
+
+	w.WriteHeader(200)
+	log.Error0(helpers.Write(w, log.Error1(helpers.SerializeJson(res))))
+}
+
+func (c *SymbolTableController) Init(di *DependencyInjection) {
+	usecase := MustNeed(di, usecases.NewSymbolTableUsecase)
+	c.uc = &usecase
+	di.Add(c)
+}
@@ -1,6 +1,19 @@
 package aragonese
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Aragonese",
+		Dir:     "aragonese",
+		Display: "Aragonese",
+		ISO6391: "an",
+		ISO6393: "arg",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
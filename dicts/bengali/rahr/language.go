@@ -1,6 +1,21 @@
 package rahr
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "BengaliRahr",
+		Dir:     "bengali/rahr",
+		Display: "Bengali (Rarh)",
+		ISO6391: "bn",
+		ISO6393: "ben",
+		Script:  "Beng",
+		Region:  "IN",
+		Variant: "rahr",
+		FS:      Language,
+	})
+}
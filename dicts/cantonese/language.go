@@ -1,6 +1,20 @@
 package cantonese
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Cantonese",
+		Dir:     "cantonese",
+		Display: "Cantonese",
+		ISO6393: "yue",
+		Script:  "Hant",
+		Region:  "HK",
+		Aliases: []string{"zh-HK", "zh-yue"},
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package catalan
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Catalan",
+		Dir:     "catalan",
+		Display: "Catalan",
+		ISO6391: "ca",
+		ISO6393: "cat",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
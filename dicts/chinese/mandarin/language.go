@@ -1,6 +1,21 @@
 package mandarin
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "ChineseMandarin",
+		Dir:     "chinese/mandarin",
+		Display: "Chinese (Mandarin)",
+		ISO6391: "zh",
+		ISO6393: "cmn",
+		Script:  "Hans",
+		Default: true,
+		Aliases: []string{"Mandarin", "Chinese"},
+		FS:      Language,
+	})
+}
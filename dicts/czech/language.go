@@ -1,6 +1,19 @@
 package czech
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Czech",
+		Dir:     "czech",
+		Display: "Czech",
+		ISO6391: "cs",
+		ISO6393: "ces",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
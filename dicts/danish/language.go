@@ -1,6 +1,19 @@
 package danish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Danish",
+		Dir:     "danish",
+		Display: "Danish",
+		ISO6391: "da",
+		ISO6393: "dan",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
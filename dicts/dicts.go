@@ -1,150 +1,37 @@
 // Package dicts provides multilingual language data for phonemization.
+//
+// Every language package registers itself in pkg/registry when imported, this
+// package imports all of them and looks the languages up in the registry. Building
+// with the goruut_subset tag leaves all of them out except those named by their
+// own tags, as in -tags goruut_subset,goruut_english,goruut_czech.
 package dicts
 
-import "github.com/neurlang/goruut/dicts/czech"
-import "github.com/neurlang/goruut/dicts/spanish"
-import "github.com/neurlang/goruut/dicts/slovak"
-import "github.com/neurlang/goruut/dicts/arabic"
-import "github.com/neurlang/goruut/dicts/farsi"
-import "github.com/neurlang/goruut/dicts/english"
-import "github.com/neurlang/goruut/dicts/german"
-import "github.com/neurlang/goruut/dicts/french"
-import "github.com/neurlang/goruut/dicts/italian"
-import "github.com/neurlang/goruut/dicts/luxembourgish"
-import "github.com/neurlang/goruut/dicts/dutch"
-import "github.com/neurlang/goruut/dicts/portuguese"
-import "github.com/neurlang/goruut/dicts/russian"
-import "github.com/neurlang/goruut/dicts/swedish"
-import "github.com/neurlang/goruut/dicts/romanian"
-import "github.com/neurlang/goruut/dicts/finnish"
-import "github.com/neurlang/goruut/dicts/isan"
-import "github.com/neurlang/goruut/dicts/swahili"
-import "github.com/neurlang/goruut/dicts/esperanto"
-import "github.com/neurlang/goruut/dicts/icelandic"
-import "github.com/neurlang/goruut/dicts/norwegian"
-import "github.com/neurlang/goruut/dicts/jamaican"
-import "github.com/neurlang/goruut/dicts/japanese"
-import "github.com/neurlang/goruut/dicts/hindi"
-import "github.com/neurlang/goruut/dicts/bengali"
-import "github.com/neurlang/goruut/dicts/bengali/dhaka"
-import "github.com/neurlang/goruut/dicts/bengali/rahr"
-import "github.com/neurlang/goruut/dicts/punjabi"
-import "github.com/neurlang/goruut/dicts/telugu"
-import "github.com/neurlang/goruut/dicts/marathi"
-import "github.com/neurlang/goruut/dicts/chinese/mandarin"
-import "github.com/neurlang/goruut/dicts/tamil"
-import "github.com/neurlang/goruut/dicts/gujarati"
-import "github.com/neurlang/goruut/dicts/urdu"
-import "github.com/neurlang/goruut/dicts/turkish"
-import "github.com/neurlang/goruut/dicts/vietnamese/southern"
-import "github.com/neurlang/goruut/dicts/vietnamese/central"
-import "github.com/neurlang/goruut/dicts/vietnamese/northern"
-import "github.com/neurlang/goruut/dicts/polish"
-import "github.com/neurlang/goruut/dicts/greek"
-import "github.com/neurlang/goruut/dicts/ukrainian"
-import "github.com/neurlang/goruut/dicts/hungarian"
-import "github.com/neurlang/goruut/dicts/malay/arab"
-import "github.com/neurlang/goruut/dicts/malay/latin"
-import "github.com/neurlang/goruut/dicts/korean"
-import "github.com/neurlang/goruut/dicts/kazakh"
-import "github.com/neurlang/goruut/dicts/afrikaans"
-import "github.com/neurlang/goruut/dicts/azerbaijani"
-import "github.com/neurlang/goruut/dicts/cebuano"
-import "github.com/neurlang/goruut/dicts/hausa"
-import "github.com/neurlang/goruut/dicts/indonesian"
-import "github.com/neurlang/goruut/dicts/danish"
-import "github.com/neurlang/goruut/dicts/malayalam"
-import "github.com/neurlang/goruut/dicts/javanese"
-import "github.com/neurlang/goruut/dicts/macedonian"
-import "github.com/neurlang/goruut/dicts/hebrew2"
-import "github.com/neurlang/goruut/dicts/hebrew3"
-import "github.com/neurlang/goruut/dicts/amharic"
-import "github.com/neurlang/goruut/dicts/belarusian"
-import "github.com/neurlang/goruut/dicts/chechen"
-import "github.com/neurlang/goruut/dicts/dzongkha"
-import "github.com/neurlang/goruut/dicts/burmese"
-import "github.com/neurlang/goruut/dicts/maltese"
-import "github.com/neurlang/goruut/dicts/mongolian"
-import "github.com/neurlang/goruut/dicts/nepali"
-import "github.com/neurlang/goruut/dicts/pashto"
-import "github.com/neurlang/goruut/dicts/tibetan"
-import "github.com/neurlang/goruut/dicts/uyghur"
-import "github.com/neurlang/goruut/dicts/thai"
-import "github.com/neurlang/goruut/dicts/zulu"
-import "github.com/neurlang/goruut/dicts/catalan"
-import "github.com/neurlang/goruut/dicts/armenian"
-import "github.com/neurlang/goruut/dicts/croatian"
-import "github.com/neurlang/goruut/dicts/serbian"
-import "github.com/neurlang/goruut/dicts/bulgarian"
-import "github.com/neurlang/goruut/dicts/chichewa"
-import "github.com/neurlang/goruut/dicts/estonian"
-import "github.com/neurlang/goruut/dicts/georgian"
-import "github.com/neurlang/goruut/dicts/latvian"
-import "github.com/neurlang/goruut/dicts/lithuanian"
-import "github.com/neurlang/goruut/dicts/tagalog"
-import "github.com/neurlang/goruut/dicts/yoruba"
-import "github.com/neurlang/goruut/dicts/basque"
-import "github.com/neurlang/goruut/dicts/galician"
-import khmer "github.com/neurlang/goruut/dicts/khmer/central"
-import "github.com/neurlang/goruut/dicts/lao"
-import "github.com/neurlang/goruut/dicts/english/american"
-import "github.com/neurlang/goruut/dicts/english/british"
-import "github.com/neurlang/goruut/dicts/albanian"
-import "github.com/neurlang/goruut/dicts/aragonese"
-import "github.com/neurlang/goruut/dicts/assamese"
-import "github.com/neurlang/goruut/dicts/bashkir"
-import "github.com/neurlang/goruut/dicts/bishnupriyamanipuri"
-import "github.com/neurlang/goruut/dicts/bosnian"
-import "github.com/neurlang/goruut/dicts/cherokee"
-import "github.com/neurlang/goruut/dicts/chuvash"
-import "github.com/neurlang/goruut/dicts/gaelic/scottish"
-import "github.com/neurlang/goruut/dicts/gaelic/irish"
-import "github.com/neurlang/goruut/dicts/greenlandic"
-import "github.com/neurlang/goruut/dicts/guarani"
-import "github.com/neurlang/goruut/dicts/haitiancreole"
-import "github.com/neurlang/goruut/dicts/hawaiian"
-import "github.com/neurlang/goruut/dicts/ido"
-import "github.com/neurlang/goruut/dicts/interlingua"
-import "github.com/neurlang/goruut/dicts/kannada"
-import "github.com/neurlang/goruut/dicts/kiche"
-import "github.com/neurlang/goruut/dicts/konkani"
-import "github.com/neurlang/goruut/dicts/kurdish"
-import "github.com/neurlang/goruut/dicts/kyrgyz"
-import "github.com/neurlang/goruut/dicts/langbelta"
-import "github.com/neurlang/goruut/dicts/latgalian"
-import latinclassical "github.com/neurlang/goruut/dicts/latin/classical"
-import "github.com/neurlang/goruut/dicts/latin/ecclesiastical"
-import "github.com/neurlang/goruut/dicts/linguafrancanova"
-import "github.com/neurlang/goruut/dicts/lojban"
-import "github.com/neurlang/goruut/dicts/lulesaami"
-import "github.com/neurlang/goruut/dicts/maori"
-import nahuatlclassical "github.com/neurlang/goruut/dicts/nahuatl/classical"
-import nahuatl "github.com/neurlang/goruut/dicts/nahuatl/central"
-import "github.com/neurlang/goruut/dicts/nahuatl/mecayapan"
-import "github.com/neurlang/goruut/dicts/nahuatl/tetelcingo"
-import "github.com/neurlang/goruut/dicts/nogai"
-import "github.com/neurlang/goruut/dicts/oromo"
-import "github.com/neurlang/goruut/dicts/papiamento"
-import "github.com/neurlang/goruut/dicts/quechua"
-import "github.com/neurlang/goruut/dicts/quenya"
-import "github.com/neurlang/goruut/dicts/setswana"
-import "github.com/neurlang/goruut/dicts/shantaiyai"
-import "github.com/neurlang/goruut/dicts/sindarin"
-import "github.com/neurlang/goruut/dicts/sindhi"
-import "github.com/neurlang/goruut/dicts/sinhala"
-import "github.com/neurlang/goruut/dicts/slovenian"
-import "github.com/neurlang/goruut/dicts/tatar"
-import "github.com/neurlang/goruut/dicts/turkmen"
-import "github.com/neurlang/goruut/dicts/uzbek"
-import "github.com/neurlang/goruut/dicts/welsh/north"
-import "github.com/neurlang/goruut/dicts/welsh/south"
-import "github.com/neurlang/goruut/dicts/cantonese"
-import "github.com/neurlang/goruut/dicts/minnan/taiwanese2"
-import "github.com/neurlang/goruut/dicts/minnan/hokkien2"
+import "github.com/neurlang/goruut/pkg/registry"
 import "errors"
+import "fmt"
+import "strings"
+
+//go:generate go run gen_include.go
+//go:generate go run gen_lexicon.go
 
 var ErrUnsupportedLanguage = errors.New("unsupportedLang")
 
+// ErrLanguageNotCompiled is returned for a language goruut has, but this build left out.
+var ErrLanguageNotCompiled = fmt.Errorf("%w: language not compiled in", ErrUnsupportedLanguage)
+
+// BuildTag returns the build tag which compiles the language in, when building with the goruut_subset tag.
+func BuildTag(name string) string {
+	return "goruut_" + strings.ToLower(name)
+}
+
+var knownLanguages = func() *registry.Registry {
+	var r registry.Registry
+	for _, l := range known {
+		r.Register(l)
+	}
+	return &r
+}()
+
 type DictGetter struct{}
 
 func (DictGetter) GetDict(lang, filename string) ([]byte, error) {
@@ -169,588 +56,22 @@ func (DictGetter) IsNewFormat(magic []byte) bool {
 	return (magic[0] == 0x78 && (magic[1] == 0x01 || magic[1] == 0x5E || magic[1] == 0x9C || magic[1] == 0xDA))
 }
 
+// GetDict reads the file of the language, given by its name, alias, directory or BCP-47 tag.
 func GetDict(lang, filename string) ([]byte, error) {
-	switch lang {
-	case "Czech":
-		return czech.Language.ReadFile(filename)
-	case "Spanish":
-		return spanish.Language.ReadFile(filename)
-	case "Slovak":
-		return slovak.Language.ReadFile(filename)
-	case "Arabic":
-		return arabic.Language.ReadFile(filename)
-	case "Farsi":
-		return farsi.Language.ReadFile(filename)
-	case "English":
-		return english.Language.ReadFile(filename)
-	case "German":
-		return german.Language.ReadFile(filename)
-	case "French":
-		return french.Language.ReadFile(filename)
-	case "Italian":
-		return italian.Language.ReadFile(filename)
-	case "Luxembourgish":
-		return luxembourgish.Language.ReadFile(filename)
-	case "Dutch":
-		return dutch.Language.ReadFile(filename)
-	case "Portuguese":
-		return portuguese.Language.ReadFile(filename)
-	case "Russian":
-		return russian.Language.ReadFile(filename)
-	case "Swedish":
-		return swedish.Language.ReadFile(filename)
-	case "Romanian":
-		return romanian.Language.ReadFile(filename)
-	case "Finnish":
-		return finnish.Language.ReadFile(filename)
-	case "Isan":
-		return isan.Language.ReadFile(filename)
-	case "Swahili":
-		return swahili.Language.ReadFile(filename)
-	case "Esperanto":
-		return esperanto.Language.ReadFile(filename)
-	case "Icelandic":
-		return icelandic.Language.ReadFile(filename)
-	case "Norwegian":
-		return norwegian.Language.ReadFile(filename)
-	case "Jamaican":
-		return jamaican.Language.ReadFile(filename)
-	case "Japanese":
-		return japanese.Language.ReadFile(filename)
-	case "Hindi":
-		return hindi.Language.ReadFile(filename)
-	case "Bengali":
-		return bengali.Language.ReadFile(filename)
-	case "BengaliDhaka":
-		return dhaka.Language.ReadFile(filename)
-	case "BengaliRahr":
-		return rahr.Language.ReadFile(filename)
-	case "Punjabi":
-		return punjabi.Language.ReadFile(filename)
-	case "Telugu":
-		return telugu.Language.ReadFile(filename)
-	case "Marathi":
-		return marathi.Language.ReadFile(filename)
-	case "ChineseMandarin":
-		return mandarin.Language.ReadFile(filename)
-	case "Tamil":
-		return tamil.Language.ReadFile(filename)
-	case "Gujarati":
-		return gujarati.Language.ReadFile(filename)
-	case "Urdu":
-		return urdu.Language.ReadFile(filename)
-	case "Turkish":
-		return turkish.Language.ReadFile(filename)
-	case "VietnameseSouthern":
-		return southern.Language.ReadFile(filename)
-	case "VietnameseCentral":
-		return central.Language.ReadFile(filename)
-	case "VietnameseNorthern":
-		return northern.Language.ReadFile(filename)
-	case "Polish":
-		return polish.Language.ReadFile(filename)
-	case "Greek":
-		return greek.Language.ReadFile(filename)
-	case "Ukrainian":
-		return ukrainian.Language.ReadFile(filename)
-	case "Hungarian":
-		return hungarian.Language.ReadFile(filename)
-	case "MalayLatin":
-		return latin.Language.ReadFile(filename)
-	case "MalayArab":
-		return arab.Language.ReadFile(filename)
-	case "Korean":
-		return korean.Language.ReadFile(filename)
-	case "Kazakh":
-		return kazakh.Language.ReadFile(filename)
-	case "Afrikaans":
-		return afrikaans.Language.ReadFile(filename)
-	case "Azerbaijani":
-		return azerbaijani.Language.ReadFile(filename)
-	case "Cebuano":
-		return cebuano.Language.ReadFile(filename)
-	case "Hausa":
-		return hausa.Language.ReadFile(filename)
-	case "Indonesian":
-		return indonesian.Language.ReadFile(filename)
-	case "Danish":
-		return danish.Language.ReadFile(filename)
-	case "Malayalam":
-		return malayalam.Language.ReadFile(filename)
-	case "Javanese":
-		return javanese.Language.ReadFile(filename)
-	case "Macedonian":
-		return macedonian.Language.ReadFile(filename)
-	case "Hebrew":
-		return hebrew3.Language.ReadFile(filename)
-	case "Hebrew2":
-		return hebrew2.Language.ReadFile(filename)
-	case "Hebrew3":
-		return hebrew3.Language.ReadFile(filename)
-	case "Amharic":
-		return amharic.Language.ReadFile(filename)
-	case "Belarusian":
-		return belarusian.Language.ReadFile(filename)
-	case "Chechen":
-		return chechen.Language.ReadFile(filename)
-	case "Dzongkha":
-		return dzongkha.Language.ReadFile(filename)
-	case "Burmese":
-		return burmese.Language.ReadFile(filename)
-	case "Maltese":
-		return maltese.Language.ReadFile(filename)
-	case "Mongolian":
-		return mongolian.Language.ReadFile(filename)
-	case "Nepali":
-		return nepali.Language.ReadFile(filename)
-	case "Pashto":
-		return pashto.Language.ReadFile(filename)
-	case "Tibetan":
-		return tibetan.Language.ReadFile(filename)
-	case "Uyghur":
-		return uyghur.Language.ReadFile(filename)
-	case "Thai":
-		return thai.Language.ReadFile(filename)
-	case "Zulu":
-		return zulu.Language.ReadFile(filename)
-	case "Catalan":
-		return catalan.Language.ReadFile(filename)
-	case "Armenian":
-		return armenian.Language.ReadFile(filename)
-	case "Croatian":
-		return croatian.Language.ReadFile(filename)
-	case "Serbian":
-		return serbian.Language.ReadFile(filename)
-	case "Bulgarian":
-		return bulgarian.Language.ReadFile(filename)
-	case "Chichewa":
-		return chichewa.Language.ReadFile(filename)
-	case "Estonian":
-		return estonian.Language.ReadFile(filename)
-	case "Georgian":
-		return georgian.Language.ReadFile(filename)
-	case "Latvian":
-		return latvian.Language.ReadFile(filename)
-	case "Lithuanian":
-		return lithuanian.Language.ReadFile(filename)
-	case "Tagalog":
-		return tagalog.Language.ReadFile(filename)
-	case "Yoruba":
-		return yoruba.Language.ReadFile(filename)
-	case "Basque":
-		return basque.Language.ReadFile(filename)
-	case "Galician":
-		return galician.Language.ReadFile(filename)
-	case "KhmerCentral":
-		return khmer.Language.ReadFile(filename)
-	case "Lao":
-		return lao.Language.ReadFile(filename)
-	case "EnglishAmerican":
-		return american.Language.ReadFile(filename)
-	case "EnglishBritish":
-		return british.Language.ReadFile(filename)
-	case "Albanian":
-		return albanian.Language.ReadFile(filename)
-	case "Aragonese":
-		return aragonese.Language.ReadFile(filename)
-	case "Assamese":
-		return assamese.Language.ReadFile(filename)
-	case "Bashkir":
-		return bashkir.Language.ReadFile(filename)
-	case "BishnupriyaManipuri":
-		return bishnupriyamanipuri.Language.ReadFile(filename)
-	case "Bosnian":
-		return bosnian.Language.ReadFile(filename)
-	case "Cherokee":
-		return cherokee.Language.ReadFile(filename)
-	case "Chuvash":
-		return chuvash.Language.ReadFile(filename)
-	case "GaelicScottish":
-		return scottish.Language.ReadFile(filename)
-	case "GaelicIrish":
-		return irish.Language.ReadFile(filename)
-	case "Greenlandic":
-		return greenlandic.Language.ReadFile(filename)
-	case "Guarani":
-		return guarani.Language.ReadFile(filename)
-	case "HaitianCreole":
-		return haitiancreole.Language.ReadFile(filename)
-	case "Hawaiian":
-		return hawaiian.Language.ReadFile(filename)
-	case "Ido":
-		return ido.Language.ReadFile(filename)
-	case "Interlingua":
-		return interlingua.Language.ReadFile(filename)
-	case "Kannada":
-		return kannada.Language.ReadFile(filename)
-	case "Kiche":
-		return kiche.Language.ReadFile(filename)
-	case "Konkani":
-		return konkani.Language.ReadFile(filename)
-	case "Kurdish":
-		return kurdish.Language.ReadFile(filename)
-	case "Kyrgyz":
-		return kyrgyz.Language.ReadFile(filename)
-	case "LangBelta":
-		return langbelta.Language.ReadFile(filename)
-	case "Latgalian":
-		return latgalian.Language.ReadFile(filename)
-	case "LatinClassical":
-		return latinclassical.Language.ReadFile(filename)
-	case "LatinEcclesiastical":
-		return ecclesiastical.Language.ReadFile(filename)
-	case "LinguaFrancaNova":
-		return linguafrancanova.Language.ReadFile(filename)
-	case "Lojban":
-		return lojban.Language.ReadFile(filename)
-	case "LuleSaami":
-		return lulesaami.Language.ReadFile(filename)
-	case "Maori":
-		return maori.Language.ReadFile(filename)
-	case "NahuatlClassical":
-		return nahuatlclassical.Language.ReadFile(filename)
-	case "NahuatlCentral":
-		return nahuatl.Language.ReadFile(filename)
-	case "NahuatlMecayapan":
-		return mecayapan.Language.ReadFile(filename)
-	case "NahuatlTetelcingo":
-		return tetelcingo.Language.ReadFile(filename)
-	case "Nogai":
-		return nogai.Language.ReadFile(filename)
-	case "Oromo":
-		return oromo.Language.ReadFile(filename)
-	case "Papiamento":
-		return papiamento.Language.ReadFile(filename)
-	case "Quechua":
-		return quechua.Language.ReadFile(filename)
-	case "Quenya":
-		return quenya.Language.ReadFile(filename)
-	case "Setswana":
-		return setswana.Language.ReadFile(filename)
-	case "ShanTaiYai":
-		return shantaiyai.Language.ReadFile(filename)
-	case "Sindarin":
-		return sindarin.Language.ReadFile(filename)
-	case "Sindhi":
-		return sindhi.Language.ReadFile(filename)
-	case "Sinhala":
-		return sinhala.Language.ReadFile(filename)
-	case "Slovenian":
-		return slovenian.Language.ReadFile(filename)
-	case "Tatar":
-		return tatar.Language.ReadFile(filename)
-	case "Turkmen":
-		return turkmen.Language.ReadFile(filename)
-	case "Uzbek":
-		return uzbek.Language.ReadFile(filename)
-	case "WelshNorth":
-		return north.Language.ReadFile(filename)
-	case "WelshSouth":
-		return south.Language.ReadFile(filename)
-	case "Cantonese":
-		return cantonese.Language.ReadFile(filename)
-	case "MinnanHokkien":
-		return hokkien2.Language.ReadFile(filename)
-	case "MinnanTaiwanese":
-		return taiwanese2.Language.ReadFile(filename)
-	case "MinnanHokkien2":
-		return hokkien2.Language.ReadFile(filename)
-	case "MinnanTaiwanese2":
-		return taiwanese2.Language.ReadFile(filename)
-	default:
-		return nil, ErrUnsupportedLanguage
+	l := registry.Resolve(lang)
+	if l == nil {
+		if l = knownLanguages.Resolve(lang); l != nil {
+			return nil, fmt.Errorf("%w: %s, build with the tags goruut_subset,%s", ErrLanguageNotCompiled, l.Name, BuildTag(l.Name))
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
 	}
+	return l.FS.ReadFile(filename)
 }
 
+// LangName returns the name of the language stored in the directory, or an empty string.
 func LangName(dir string) string {
-	switch dir {
-	case "afrikaans":
-		return "Afrikaans"
-	case "amharic":
-		return "Amharic"
-	case "arabic":
-		return "Arabic"
-	case "armenian":
-		return "Armenian"
-	case "azerbaijani":
-		return "Azerbaijani"
-	case "basque":
-		return "Basque"
-	case "belarusian":
-		return "Belarusian"
-	case "bengali":
-		return "Bengali"
-	case "bengali/dhaka":
-		return "BengaliDhaka"
-	case "bengali/rahr":
-		return "BengaliRahr"
-	case "bulgarian":
-		return "Bulgarian"
-	case "burmese":
-		return "Burmese"
-	case "catalan":
-		return "Catalan"
-	case "cebuano":
-		return "Cebuano"
-	case "chechen":
-		return "Chechen"
-	case "chichewa":
-		return "Chichewa"
-	case "chinese/mandarin":
-		return "ChineseMandarin"
-	case "croatian":
-		return "Croatian"
-	case "czech":
-		return "Czech"
-	case "danish":
-		return "Danish"
-	case "dutch":
-		return "Dutch"
-	case "dzongkha":
-		return "Dzongkha"
-	case "english":
-		return "English"
-	case "english/american":
-		return "EnglishAmerican"
-	case "english/british":
-		return "EnglishBritish"
-	case "esperanto":
-		return "Esperanto"
-	case "estonian":
-		return "Estonian"
-	case "farsi":
-		return "Farsi"
-	case "finnish":
-		return "Finnish"
-	case "french":
-		return "French"
-	case "galician":
-		return "Galician"
-	case "georgian":
-		return "Georgian"
-	case "german":
-		return "German"
-	case "greek":
-		return "Greek"
-	case "gujarati":
-		return "Gujarati"
-	case "hausa":
-		return "Hausa"
-	case "hebrew":
-		return "Hebrew"
-	case "hebrew2":
-		return "Hebrew2"
-	case "hebrew3":
-		return "Hebrew3"
-	case "hindi":
-		return "Hindi"
-	case "hungarian":
-		return "Hungarian"
-	case "icelandic":
-		return "Icelandic"
-	case "indonesian":
-		return "Indonesian"
-	case "isan":
-		return "Isan"
-	case "italian":
-		return "Italian"
-	case "jamaican":
-		return "Jamaican"
-	case "japanese":
-		return "Japanese"
-	case "javanese":
-		return "Javanese"
-	case "kazakh":
-		return "Kazakh"
-	case "khmer/central":
-		return "KhmerCentral"
-	case "korean":
-		return "Korean"
-	case "lao":
-		return "Lao"
-	case "latvian":
-		return "Latvian"
-	case "lithuanian":
-		return "Lithuanian"
-	case "luxembourgish":
-		return "Luxembourgish"
-	case "macedonian":
-		return "Macedonian"
-	case "malay/arab":
-		return "Malayalam"
-	case "malay/latin":
-		return "MalayArab"
-	case "malayalam":
-		return "MalayLatin"
-	case "maltese":
-		return "Maltese"
-	case "marathi":
-		return "Marathi"
-	case "mongolian":
-		return "Mongolian"
-	case "nepali":
-		return "Nepali"
-	case "norwegian":
-		return "Norwegian"
-	case "pashto":
-		return "Pashto"
-	case "polish":
-		return "Polish"
-	case "portuguese":
-		return "Portuguese"
-	case "punjabi":
-		return "Punjabi"
-	case "romanian":
-		return "Romanian"
-	case "russian":
-		return "Russian"
-	case "serbian":
-		return "Serbian"
-	case "slovak":
-		return "Slovak"
-	case "spanish":
-		return "Spanish"
-	case "swahili":
-		return "Swahili"
-	case "swedish":
-		return "Swedish"
-	case "tagalog":
-		return "Tagalog"
-	case "tamil":
-		return "Tamil"
-	case "telugu":
-		return "Telugu"
-	case "thai":
-		return "Thai"
-	case "tibetan":
-		return "Tibetan"
-	case "turkish":
-		return "Turkish"
-	case "ukrainian":
-		return "Ukrainian"
-	case "urdu":
-		return "Urdu"
-	case "uyghur":
-		return "Uyghur"
-	case "vietnamese/central":
-		return "VietnameseCentral"
-	case "vietnamese/northern":
-		return "VietnameseNorthern"
-	case "vietnamese/southern":
-		return "VietnameseSouthern"
-	case "yoruba":
-		return "Yoruba"
-	case "zulu":
-		return "Zulu"
-	case "albanian":
-		return "Albanian"
-	case "aragonese":
-		return "Aragonese"
-	case "assamese":
-		return "Assamese"
-	case "bashkir":
-		return "Bashkir"
-	case "bishnupriyamanipuri":
-		return "BishnupriyaManipuri"
-	case "bosnian":
-		return "Bosnian"
-	case "cherokee":
-		return "Cherokee"
-	case "chuvash":
-		return "Chuvash"
-	case "gaelic/scottish":
-		return "GaelicScottish"
-	case "gaelic/irish":
-		return "GaelicIrish"
-	case "greenlandic":
-		return "Greenlandic"
-	case "guarani":
-		return "Guarani"
-	case "haitiancreole":
-		return "HaitianCreole"
-	case "hawaiian":
-		return "Hawaiian"
-	case "ido":
-		return "Ido"
-	case "interlingua":
-		return "Interlingua"
-	case "kannada":
-		return "Kannada"
-	case "kiche":
-		return "Kiche"
-	case "konkani":
-		return "Konkani"
-	case "kurdish":
-		return "Kurdish"
-	case "kyrgyz":
-		return "Kyrgyz"
-	case "langbelta":
-		return "LangBelta"
-	case "latgalian":
-		return "Latgalian"
-	case "latin/classical":
-		return "LatinClassical"
-	case "latin/ecclesiastical":
-		return "LatinEcclesiastical"
-	case "linguafrancanova":
-		return "LinguaFrancaNova"
-	case "lojban":
-		return "Lojban"
-	case "lulesaami":
-		return "LuleSaami"
-	case "maori":
-		return "Maori"
-	case "nahuatl/classical":
-		return "NahuatlClassical"
-	case "nahuatl/central":
-		return "NahuatlCentral"
-	case "nahuatl/mecayapan":
-		return "NahuatlMecayapan"
-	case "nahuatl/tetelcingo":
-		return "NahuatlTetelcingo"
-	case "nogai":
-		return "Nogai"
-	case "oromo":
-		return "Oromo"
-	case "papiamento":
-		return "Papiamento"
-	case "quechua":
-		return "Quechua"
-	case "quenya":
-		return "Quenya"
-	case "setswana":
-		return "Setswana"
-	case "shantaiyai":
-		return "ShanTaiYai"
-	case "sindarin":
-		return "Sindarin"
-	case "sindhi":
-		return "Sindhi"
-	case "sinhala":
-		return "Sinhala"
-	case "slovenian":
-		return "Slovenian"
-	case "tatar":
-		return "Tatar"
-	case "turkmen":
-		return "Turkmen"
-	case "uzbek":
-		return "Uzbek"
-	case "welsh/north":
-		return "WelshNorth"
-	case "welsh/south":
-		return "WelshSouth"
-	case "cantonese":
-		return "Cantonese"
-	case "minnan/hokkien":
-		return "MinnanHokkien"
-	case "minnan/taiwanese":
-		return "MinnanTaiwanese"
-	case "minnan/hokkien2":
-		return "MinnanHokkien2"
-	case "minnan/taiwanese2":
-		return "MinnanTaiwanese2"
-	default:
-		return ""
+	if l := registry.Lookup(dir); l != nil {
+		return l.Name
 	}
+	return ""
 }
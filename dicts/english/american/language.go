@@ -1,6 +1,20 @@
 package american
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "EnglishAmerican",
+		Dir:     "english/american",
+		Display: "English (American)",
+		ISO6391: "en",
+		ISO6393: "eng",
+		Script:  "Latn",
+		Region:  "US",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,20 @@
 package british
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "EnglishBritish",
+		Dir:     "english/british",
+		Display: "English (British)",
+		ISO6391: "en",
+		ISO6393: "eng",
+		Script:  "Latn",
+		Region:  "GB",
+		FS:      Language,
+	})
+}
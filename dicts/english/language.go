@@ -1,6 +1,19 @@
 package english
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "English",
+		Dir:     "english",
+		Display: "English",
+		ISO6391: "en",
+		ISO6393: "eng",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,20 @@
 package farsi
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Farsi",
+		Dir:     "farsi",
+		Display: "Persian",
+		ISO6391: "fa",
+		ISO6393: "fas",
+		Script:  "Arab",
+		Aliases: []string{"Persian"},
+		FS:      Language,
+	})
+}
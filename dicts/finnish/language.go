@@ -1,6 +1,19 @@
 package finnish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Finnish",
+		Dir:     "finnish",
+		Display: "Finnish",
+		ISO6391: "fi",
+		ISO6393: "fin",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,20 @@
 package irish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "GaelicIrish",
+		Dir:     "gaelic/irish",
+		Display: "Irish",
+		ISO6391: "ga",
+		ISO6393: "gle",
+		Script:  "Latn",
+		Aliases: []string{"Irish"},
+		FS:      Language,
+	})
+}
@@ -1,6 +1,20 @@
 package scottish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "GaelicScottish",
+		Dir:     "gaelic/scottish",
+		Display: "Scottish Gaelic",
+		ISO6391: "gd",
+		ISO6393: "gla",
+		Script:  "Latn",
+		Aliases: []string{"ScottishGaelic"},
+		FS:      Language,
+	})
+}
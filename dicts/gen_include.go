@@ -0,0 +1,117 @@
+//go:build ignore
+
+// gen_include finds the language packages under dicts and writes a file importing
+// each of them behind its build tag, and the table of known languages used to
+// report the languages left out of a build. Run it by go generate after adding a
+// language.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// language holds the fields of the registry.Language literal of a language package.
+type language struct {
+	dir    string
+	fields map[string]string
+}
+
+func parse(path string) (*language, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var lang *language
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if sel, ok := lit.Type.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Language" {
+			return true
+		}
+		lang = &language{dir: filepath.ToSlash(filepath.Dir(path)), fields: make(map[string]string)}
+		for _, elt := range lit.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			key := kv.Key.(*ast.Ident).Name
+			if key == "FS" {
+				continue
+			}
+			var buf bytes.Buffer
+			format.Node(&buf, token.NewFileSet(), kv.Value)
+			lang.fields[key] = buf.String()
+		}
+		return false
+	})
+	if lang == nil {
+		return nil, fmt.Errorf("%s: no registry.Language", path)
+	}
+	return lang, nil
+}
+
+func write(name string, src []byte) {
+	src, err := format.Source(src)
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(name, src, 0644); err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	var langs []*language
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "language.go" {
+			return err
+		}
+		lang, err := parse(path)
+		langs = append(langs, lang)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+	old, _ := filepath.Glob("lang_*.go")
+	for _, name := range old {
+		if err := os.Remove(name); err != nil {
+			panic(err)
+		}
+	}
+	var known bytes.Buffer
+	fmt.Fprintf(&known, "// Code generated by gen_include.go; DO NOT EDIT.\n\npackage dicts\n\n")
+	fmt.Fprintf(&known, "import \"github.com/neurlang/goruut/pkg/registry\"\n\n")
+	fmt.Fprintf(&known, "// known lists every language goruut has, compiled in or not, without its files.\n")
+	fmt.Fprintf(&known, "var known = []registry.Language{\n")
+	for _, l := range langs {
+		name, err := strconv.Unquote(l.fields["Name"])
+		if err != nil {
+			panic(fmt.Errorf("%s: %w", l.dir, err))
+		}
+		var include bytes.Buffer
+		fmt.Fprintf(&include, "// Code generated by gen_include.go; DO NOT EDIT.\n\n")
+		fmt.Fprintf(&include, "//go:build !goruut_subset || goruut_%s\n\npackage dicts\n\n", strings.ToLower(name))
+		fmt.Fprintf(&include, "import _ \"github.com/neurlang/goruut/dicts/%s\"\n", l.dir)
+		write("lang_"+strings.ReplaceAll(l.dir, "/", "_")+".go", include.Bytes())
+
+		fmt.Fprintf(&known, "{")
+		for _, key := range []string{"Name", "Dir", "Display", "ISO6391", "ISO6393", "Script", "Region", "Variant", "Default", "Aliases"} {
+			if value, ok := l.fields[key]; ok {
+				fmt.Fprintf(&known, "%s: %s, ", key, value)
+			}
+		}
+		fmt.Fprintf(&known, "},\n")
+	}
+	fmt.Fprintf(&known, "}\n")
+	write("known.go", known.Bytes())
+}
@@ -0,0 +1,64 @@
+//go:build ignore
+
+// gen_lexicon compiles the lexicon of each language, missing.all.zlib, into the
+// compact lexicon.zlib the language embeds, see pkg/lexicon. A lexicon compiled from
+// the current missing.all.zlib is kept. Run it by go generate after changing a lexicon.
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/neurlang/goruut/pkg/lexicon"
+)
+
+func compile(dir string) error {
+	source, err := os.ReadFile(filepath.Join(dir, lexicon.SourceName))
+	if err != nil {
+		return err
+	}
+	var sum = sha256.Sum256(source)
+	var target = filepath.Join(dir, lexicon.FileName)
+	if compiled, err := os.ReadFile(target); err == nil {
+		if l, err := lexicon.Read(compiled); err == nil && l.Source() == sum {
+			return nil
+		}
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(source))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	var b lexicon.Builder
+	b.SetSource(sum)
+	// the repository tags every lexicon record as dict
+	if err := b.ReadTSV(reader, "dict"); err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := b.Write(&out); err != nil {
+		return err
+	}
+	fmt.Println("compiled", target)
+	return os.WriteFile(target, out.Bytes(), 0644)
+}
+
+func main() {
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "language.go" {
+			return err
+		}
+		if err := compile(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Dir(path), err)
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
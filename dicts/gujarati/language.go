@@ -1,6 +1,19 @@
 package gujarati
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Gujarati",
+		Dir:     "gujarati",
+		Display: "Gujarati",
+		ISO6391: "gu",
+		ISO6393: "guj",
+		Script:  "Gujr",
+		FS:      Language,
+	})
+}
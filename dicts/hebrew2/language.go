@@ -1,6 +1,20 @@
 package hebrew2
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Hebrew2",
+		Dir:     "hebrew2",
+		Display: "Hebrew (vocalized)",
+		ISO6391: "he",
+		ISO6393: "heb",
+		Script:  "Hebr",
+		Variant: "vocalized",
+		FS:      Language,
+	})
+}
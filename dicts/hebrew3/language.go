@@ -1,6 +1,21 @@
 package hebrew3
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Hebrew3",
+		Dir:     "hebrew3",
+		Display: "Hebrew",
+		ISO6391: "he",
+		ISO6393: "heb",
+		Script:  "Hebr",
+		Default: true,
+		Aliases: []string{"Hebrew"},
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package hindi
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Hindi",
+		Dir:     "hindi",
+		Display: "Hindi",
+		ISO6391: "hi",
+		ISO6393: "hin",
+		Script:  "Deva",
+		FS:      Language,
+	})
+}
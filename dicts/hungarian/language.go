@@ -1,6 +1,19 @@
 package hungarian
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Hungarian",
+		Dir:     "hungarian",
+		Display: "Hungarian",
+		ISO6391: "hu",
+		ISO6393: "hun",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
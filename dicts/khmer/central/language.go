@@ -1,6 +1,20 @@
 package khmer
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "KhmerCentral",
+		Dir:     "khmer/central",
+		Display: "Khmer",
+		ISO6391: "km",
+		ISO6393: "khm",
+		Script:  "Khmr",
+		Aliases: []string{"Khmer"},
+		FS:      Language,
+	})
+}
@@ -0,0 +1,149 @@
+// Code generated by gen_include.go; DO NOT EDIT.
+
+package dicts
+
+import "github.com/neurlang/goruut/pkg/registry"
+
+// known lists every language goruut has, compiled in or not, without its files.
+var known = []registry.Language{
+	{Name: "Afrikaans", Dir: "afrikaans", Display: "Afrikaans", ISO6391: "af", ISO6393: "afr", Script: "Latn"},
+	{Name: "Albanian", Dir: "albanian", Display: "Albanian", ISO6391: "sq", ISO6393: "sqi", Script: "Latn"},
+	{Name: "Amharic", Dir: "amharic", Display: "Amharic", ISO6391: "am", ISO6393: "amh", Script: "Ethi"},
+	{Name: "Arabic", Dir: "arabic", Display: "Arabic", ISO6391: "ar", ISO6393: "ara", Script: "Arab"},
+	{Name: "Aragonese", Dir: "aragonese", Display: "Aragonese", ISO6391: "an", ISO6393: "arg", Script: "Latn"},
+	{Name: "Armenian", Dir: "armenian", Display: "Armenian", ISO6391: "hy", ISO6393: "hye", Script: "Armn"},
+	{Name: "Assamese", Dir: "assamese", Display: "Assamese", ISO6391: "as", ISO6393: "asm", Script: "Beng"},
+	{Name: "Azerbaijani", Dir: "azerbaijani", Display: "Azerbaijani", ISO6391: "az", ISO6393: "aze", Script: "Latn"},
+	{Name: "Bashkir", Dir: "bashkir", Display: "Bashkir", ISO6391: "ba", ISO6393: "bak", Script: "Cyrl"},
+	{Name: "Basque", Dir: "basque", Display: "Basque", ISO6391: "eu", ISO6393: "eus", Script: "Latn"},
+	{Name: "Belarusian", Dir: "belarusian", Display: "Belarusian", ISO6391: "be", ISO6393: "bel", Script: "Cyrl"},
+	{Name: "BengaliDhaka", Dir: "bengali/dhaka", Display: "Bengali (Dhaka)", ISO6391: "bn", ISO6393: "ben", Script: "Beng", Region: "BD", Variant: "dhaka"},
+	{Name: "Bengali", Dir: "bengali", Display: "Bengali", ISO6391: "bn", ISO6393: "ben", Script: "Beng"},
+	{Name: "BengaliRahr", Dir: "bengali/rahr", Display: "Bengali (Rarh)", ISO6391: "bn", ISO6393: "ben", Script: "Beng", Region: "IN", Variant: "rahr"},
+	{Name: "BishnupriyaManipuri", Dir: "bishnupriyamanipuri", Display: "Bishnupriya Manipuri", ISO6393: "bpy", Script: "Beng"},
+	{Name: "Bosnian", Dir: "bosnian", Display: "Bosnian", ISO6391: "bs", ISO6393: "bos", Script: "Latn"},
+	{Name: "Bulgarian", Dir: "bulgarian", Display: "Bulgarian", ISO6391: "bg", ISO6393: "bul", Script: "Cyrl"},
+	{Name: "Burmese", Dir: "burmese", Display: "Burmese", ISO6391: "my", ISO6393: "mya", Script: "Mymr"},
+	{Name: "Cantonese", Dir: "cantonese", Display: "Cantonese", ISO6393: "yue", Script: "Hant", Region: "HK", Aliases: []string{"zh-HK", "zh-yue"}},
+	{Name: "Catalan", Dir: "catalan", Display: "Catalan", ISO6391: "ca", ISO6393: "cat", Script: "Latn"},
+	{Name: "Cebuano", Dir: "cebuano", Display: "Cebuano", ISO6393: "ceb", Script: "Latn"},
+	{Name: "Chechen", Dir: "chechen", Display: "Chechen", ISO6391: "ce", ISO6393: "che", Script: "Cyrl"},
+	{Name: "Cherokee", Dir: "cherokee", Display: "Cherokee", ISO6393: "chr", Script: "Cher"},
+	{Name: "Chichewa", Dir: "chichewa", Display: "Chichewa", ISO6391: "ny", ISO6393: "nya", Script: "Latn"},
+	{Name: "ChineseMandarin", Dir: "chinese/mandarin", Display: "Chinese (Mandarin)", ISO6391: "zh", ISO6393: "cmn", Script: "Hans", Default: true, Aliases: []string{"Mandarin", "Chinese"}},
+	{Name: "Chuvash", Dir: "chuvash", Display: "Chuvash", ISO6391: "cv", ISO6393: "chv", Script: "Cyrl"},
+	{Name: "Croatian", Dir: "croatian", Display: "Croatian", ISO6391: "hr", ISO6393: "hrv", Script: "Latn"},
+	{Name: "Czech", Dir: "czech", Display: "Czech", ISO6391: "cs", ISO6393: "ces", Script: "Latn"},
+	{Name: "Danish", Dir: "danish", Display: "Danish", ISO6391: "da", ISO6393: "dan", Script: "Latn"},
+	{Name: "Dutch", Dir: "dutch", Display: "Dutch", ISO6391: "nl", ISO6393: "nld", Script: "Latn"},
+	{Name: "Dzongkha", Dir: "dzongkha", Display: "Dzongkha", ISO6391: "dz", ISO6393: "dzo", Script: "Tibt"},
+	{Name: "EnglishAmerican", Dir: "english/american", Display: "English (American)", ISO6391: "en", ISO6393: "eng", Script: "Latn", Region: "US"},
+	{Name: "EnglishBritish", Dir: "english/british", Display: "English (British)", ISO6391: "en", ISO6393: "eng", Script: "Latn", Region: "GB"},
+	{Name: "English", Dir: "english", Display: "English", ISO6391: "en", ISO6393: "eng", Script: "Latn"},
+	{Name: "Esperanto", Dir: "esperanto", Display: "Esperanto", ISO6391: "eo", ISO6393: "epo", Script: "Latn"},
+	{Name: "Estonian", Dir: "estonian", Display: "Estonian", ISO6391: "et", ISO6393: "est", Script: "Latn"},
+	{Name: "Farsi", Dir: "farsi", Display: "Persian", ISO6391: "fa", ISO6393: "fas", Script: "Arab", Aliases: []string{"Persian"}},
+	{Name: "Finnish", Dir: "finnish", Display: "Finnish", ISO6391: "fi", ISO6393: "fin", Script: "Latn"},
+	{Name: "French", Dir: "french", Display: "French", ISO6391: "fr", ISO6393: "fra", Script: "Latn"},
+	{Name: "GaelicIrish", Dir: "gaelic/irish", Display: "Irish", ISO6391: "ga", ISO6393: "gle", Script: "Latn", Aliases: []string{"Irish"}},
+	{Name: "GaelicScottish", Dir: "gaelic/scottish", Display: "Scottish Gaelic", ISO6391: "gd", ISO6393: "gla", Script: "Latn", Aliases: []string{"ScottishGaelic"}},
+	{Name: "Galician", Dir: "galician", Display: "Galician", ISO6391: "gl", ISO6393: "glg", Script: "Latn"},
+	{Name: "Georgian", Dir: "georgian", Display: "Georgian", ISO6391: "ka", ISO6393: "kat", Script: "Geor"},
+	{Name: "German", Dir: "german", Display: "German", ISO6391: "de", ISO6393: "deu", Script: "Latn"},
+	{Name: "Greek", Dir: "greek", Display: "Greek", ISO6391: "el", ISO6393: "ell", Script: "Grek"},
+	{Name: "Greenlandic", Dir: "greenlandic", Display: "Greenlandic", ISO6391: "kl", ISO6393: "kal", Script: "Latn"},
+	{Name: "Guarani", Dir: "guarani", Display: "Guarani", ISO6391: "gn", ISO6393: "grn", Script: "Latn"},
+	{Name: "Gujarati", Dir: "gujarati", Display: "Gujarati", ISO6391: "gu", ISO6393: "guj", Script: "Gujr"},
+	{Name: "HaitianCreole", Dir: "haitiancreole", Display: "Haitian Creole", ISO6391: "ht", ISO6393: "hat", Script: "Latn"},
+	{Name: "Hausa", Dir: "hausa", Display: "Hausa", ISO6391: "ha", ISO6393: "hau", Script: "Latn"},
+	{Name: "Hawaiian", Dir: "hawaiian", Display: "Hawaiian", ISO6393: "haw", Script: "Latn"},
+	{Name: "Hebrew2", Dir: "hebrew2", Display: "Hebrew (vocalized)", ISO6391: "he", ISO6393: "heb", Script: "Hebr", Variant: "vocalized"},
+	{Name: "Hebrew3", Dir: "hebrew3", Display: "Hebrew", ISO6391: "he", ISO6393: "heb", Script: "Hebr", Default: true, Aliases: []string{"Hebrew"}},
+	{Name: "Hindi", Dir: "hindi", Display: "Hindi", ISO6391: "hi", ISO6393: "hin", Script: "Deva"},
+	{Name: "Hungarian", Dir: "hungarian", Display: "Hungarian", ISO6391: "hu", ISO6393: "hun", Script: "Latn"},
+	{Name: "Icelandic", Dir: "icelandic", Display: "Icelandic", ISO6391: "is", ISO6393: "isl", Script: "Latn"},
+	{Name: "Ido", Dir: "ido", Display: "Ido", ISO6391: "io", ISO6393: "ido", Script: "Latn"},
+	{Name: "Indonesian", Dir: "indonesian", Display: "Indonesian", ISO6391: "id", ISO6393: "ind", Script: "Latn"},
+	{Name: "Interlingua", Dir: "interlingua", Display: "Interlingua", ISO6391: "ia", ISO6393: "ina", Script: "Latn"},
+	{Name: "Isan", Dir: "isan", Display: "Isan", ISO6393: "tts", Script: "Thai"},
+	{Name: "Italian", Dir: "italian", Display: "Italian", ISO6391: "it", ISO6393: "ita", Script: "Latn"},
+	{Name: "Jamaican", Dir: "jamaican", Display: "Jamaican Patois", ISO6393: "jam", Script: "Latn"},
+	{Name: "Japanese", Dir: "japanese", Display: "Japanese", ISO6391: "ja", ISO6393: "jpn", Script: "Jpan"},
+	{Name: "Javanese", Dir: "javanese", Display: "Javanese", ISO6391: "jv", ISO6393: "jav", Script: "Java"},
+	{Name: "Kannada", Dir: "kannada", Display: "Kannada", ISO6391: "kn", ISO6393: "kan", Script: "Knda"},
+	{Name: "Kazakh", Dir: "kazakh", Display: "Kazakh", ISO6391: "kk", ISO6393: "kaz", Script: "Cyrl"},
+	{Name: "KhmerCentral", Dir: "khmer/central", Display: "Khmer", ISO6391: "km", ISO6393: "khm", Script: "Khmr", Aliases: []string{"Khmer"}},
+	{Name: "Kiche", Dir: "kiche", Display: "K'iche'", ISO6393: "quc", Script: "Latn"},
+	{Name: "Konkani", Dir: "konkani", Display: "Konkani", ISO6393: "kok", Script: "Deva"},
+	{Name: "Korean", Dir: "korean", Display: "Korean", ISO6391: "ko", ISO6393: "kor", Script: "Hang"},
+	{Name: "Kurdish", Dir: "kurdish", Display: "Kurdish", ISO6391: "ku", ISO6393: "kur", Script: "Arab"},
+	{Name: "Kyrgyz", Dir: "kyrgyz", Display: "Kyrgyz", ISO6391: "ky", ISO6393: "kir", Script: "Cyrl"},
+	{Name: "LangBelta", Dir: "langbelta", Display: "Lang Belta", Script: "Latn"},
+	{Name: "Lao", Dir: "lao", Display: "Lao", ISO6391: "lo", ISO6393: "lao", Script: "Laoo"},
+	{Name: "Latgalian", Dir: "latgalian", Display: "Latgalian", ISO6393: "ltg", Script: "Latn"},
+	{Name: "LatinClassical", Dir: "latin/classical", Display: "Latin (Classical)", ISO6391: "la", ISO6393: "lat", Script: "Latn", Variant: "classical", Default: true, Aliases: []string{"Latin"}},
+	{Name: "LatinEcclesiastical", Dir: "latin/ecclesiastical", Display: "Latin (Ecclesiastical)", ISO6391: "la", ISO6393: "lat", Script: "Latn", Variant: "ecclesiastical"},
+	{Name: "Latvian", Dir: "latvian", Display: "Latvian", ISO6391: "lv", ISO6393: "lav", Script: "Latn"},
+	{Name: "LinguaFrancaNova", Dir: "linguafrancanova", Display: "Lingua Franca Nova", ISO6393: "lfn", Script: "Latn"},
+	{Name: "Lithuanian", Dir: "lithuanian", Display: "Lithuanian", ISO6391: "lt", ISO6393: "lit", Script: "Latn"},
+	{Name: "Lojban", Dir: "lojban", Display: "Lojban", ISO6393: "jbo", Script: "Latn"},
+	{Name: "LuleSaami", Dir: "lulesaami", Display: "Lule Sami", ISO6393: "smj", Script: "Latn"},
+	{Name: "Luxembourgish", Dir: "luxembourgish", Display: "Luxembourgish", ISO6391: "lb", ISO6393: "ltz", Script: "Latn"},
+	{Name: "Macedonian", Dir: "macedonian", Display: "Macedonian", ISO6391: "mk", ISO6393: "mkd", Script: "Cyrl"},
+	{Name: "MalayArab", Dir: "malay/arab", Display: "Malay (Jawi)", ISO6391: "ms", ISO6393: "msa", Script: "Arab"},
+	{Name: "MalayLatin", Dir: "malay/latin", Display: "Malay (Latin)", ISO6391: "ms", ISO6393: "msa", Script: "Latn", Default: true, Aliases: []string{"Malay"}},
+	{Name: "Malayalam", Dir: "malayalam", Display: "Malayalam", ISO6391: "ml", ISO6393: "mal", Script: "Mlym"},
+	{Name: "Maltese", Dir: "maltese", Display: "Maltese", ISO6391: "mt", ISO6393: "mlt", Script: "Latn"},
+	{Name: "Maori", Dir: "maori", Display: "Maori", ISO6391: "mi", ISO6393: "mri", Script: "Latn"},
+	{Name: "Marathi", Dir: "marathi", Display: "Marathi", ISO6391: "mr", ISO6393: "mar", Script: "Deva"},
+	{Name: "MinnanHokkien2", Dir: "minnan/hokkien2", Display: "Min Nan (Hokkien)", ISO6393: "nan", Script: "Hant", Variant: "hokkien", Default: true, Aliases: []string{"MinnanHokkien"}},
+	{Name: "MinnanTaiwanese2", Dir: "minnan/taiwanese2", Display: "Min Nan (Taiwanese)", ISO6393: "nan", Script: "Hant", Region: "TW", Variant: "taiwanese", Aliases: []string{"MinnanTaiwanese"}},
+	{Name: "Mongolian", Dir: "mongolian", Display: "Mongolian", ISO6391: "mn", ISO6393: "mon", Script: "Cyrl"},
+	{Name: "NahuatlCentral", Dir: "nahuatl/central", Display: "Nahuatl (Central)", ISO6393: "nhn", Script: "Latn"},
+	{Name: "NahuatlClassical", Dir: "nahuatl/classical", Display: "Nahuatl (Classical)", ISO6393: "nci", Script: "Latn"},
+	{Name: "NahuatlMecayapan", Dir: "nahuatl/mecayapan", Display: "Nahuatl (Mecayapan)", ISO6393: "nhx", Script: "Latn"},
+	{Name: "NahuatlTetelcingo", Dir: "nahuatl/tetelcingo", Display: "Nahuatl (Tetelcingo)", ISO6393: "nhg", Script: "Latn"},
+	{Name: "Nepali", Dir: "nepali", Display: "Nepali", ISO6391: "ne", ISO6393: "nep", Script: "Deva"},
+	{Name: "Nogai", Dir: "nogai", Display: "Nogai", ISO6393: "nog", Script: "Cyrl"},
+	{Name: "Norwegian", Dir: "norwegian", Display: "Norwegian", ISO6391: "no", ISO6393: "nor", Script: "Latn", Aliases: []string{"nb"}},
+	{Name: "Oromo", Dir: "oromo", Display: "Oromo", ISO6391: "om", ISO6393: "orm", Script: "Latn"},
+	{Name: "Papiamento", Dir: "papiamento", Display: "Papiamento", ISO6393: "pap", Script: "Latn"},
+	{Name: "Pashto", Dir: "pashto", Display: "Pashto", ISO6391: "ps", ISO6393: "pus", Script: "Arab"},
+	{Name: "Polish", Dir: "polish", Display: "Polish", ISO6391: "pl", ISO6393: "pol", Script: "Latn"},
+	{Name: "Portuguese", Dir: "portuguese", Display: "Portuguese", ISO6391: "pt", ISO6393: "por", Script: "Latn"},
+	{Name: "Punjabi", Dir: "punjabi", Display: "Punjabi", ISO6391: "pa", ISO6393: "pan", Script: "Guru"},
+	{Name: "Quechua", Dir: "quechua", Display: "Quechua", ISO6391: "qu", ISO6393: "que", Script: "Latn"},
+	{Name: "Quenya", Dir: "quenya", Display: "Quenya", ISO6393: "qya", Script: "Latn"},
+	{Name: "Romanian", Dir: "romanian", Display: "Romanian", ISO6391: "ro", ISO6393: "ron", Script: "Latn"},
+	{Name: "Russian", Dir: "russian", Display: "Russian", ISO6391: "ru", ISO6393: "rus", Script: "Cyrl"},
+	{Name: "Serbian", Dir: "serbian", Display: "Serbian", ISO6391: "sr", ISO6393: "srp", Script: "Cyrl"},
+	{Name: "Setswana", Dir: "setswana", Display: "Setswana", ISO6391: "tn", ISO6393: "tsn", Script: "Latn", Aliases: []string{"Tswana"}},
+	{Name: "ShanTaiYai", Dir: "shantaiyai", Display: "Shan", ISO6393: "shn", Script: "Mymr"},
+	{Name: "Sindarin", Dir: "sindarin", Display: "Sindarin", ISO6393: "sjn", Script: "Latn"},
+	{Name: "Sindhi", Dir: "sindhi", Display: "Sindhi", ISO6391: "sd", ISO6393: "snd", Script: "Arab"},
+	{Name: "Sinhala", Dir: "sinhala", Display: "Sinhala", ISO6391: "si", ISO6393: "sin", Script: "Sinh"},
+	{Name: "Slovak", Dir: "slovak", Display: "Slovak", ISO6391: "sk", ISO6393: "slk", Script: "Latn"},
+	{Name: "Slovenian", Dir: "slovenian", Display: "Slovenian", ISO6391: "sl", ISO6393: "slv", Script: "Latn"},
+	{Name: "Spanish", Dir: "spanish", Display: "Spanish", ISO6391: "es", ISO6393: "spa", Script: "Latn"},
+	{Name: "Swahili", Dir: "swahili", Display: "Swahili", ISO6391: "sw", ISO6393: "swa", Script: "Latn"},
+	{Name: "Swedish", Dir: "swedish", Display: "Swedish", ISO6391: "sv", ISO6393: "swe", Script: "Latn"},
+	{Name: "Tagalog", Dir: "tagalog", Display: "Tagalog", ISO6391: "tl", ISO6393: "tgl", Script: "Latn"},
+	{Name: "Tamil", Dir: "tamil", Display: "Tamil", ISO6391: "ta", ISO6393: "tam", Script: "Taml"},
+	{Name: "Tatar", Dir: "tatar", Display: "Tatar", ISO6391: "tt", ISO6393: "tat", Script: "Cyrl"},
+	{Name: "Telugu", Dir: "telugu", Display: "Telugu", ISO6391: "te", ISO6393: "tel", Script: "Telu"},
+	{Name: "Thai", Dir: "thai", Display: "Thai", ISO6391: "th", ISO6393: "tha", Script: "Thai"},
+	{Name: "Tibetan", Dir: "tibetan", Display: "Tibetan", ISO6391: "bo", ISO6393: "bod", Script: "Tibt"},
+	{Name: "Turkish", Dir: "turkish", Display: "Turkish", ISO6391: "tr", ISO6393: "tur", Script: "Latn"},
+	{Name: "Turkmen", Dir: "turkmen", Display: "Turkmen", ISO6391: "tk", ISO6393: "tuk", Script: "Latn"},
+	{Name: "Ukrainian", Dir: "ukrainian", Display: "Ukrainian", ISO6391: "uk", ISO6393: "ukr", Script: "Cyrl"},
+	{Name: "Urdu", Dir: "urdu", Display: "Urdu", ISO6391: "ur", ISO6393: "urd", Script: "Arab"},
+	{Name: "Uyghur", Dir: "uyghur", Display: "Uyghur", ISO6391: "ug", ISO6393: "uig", Script: "Arab"},
+	{Name: "Uzbek", Dir: "uzbek", Display: "Uzbek", ISO6391: "uz", ISO6393: "uzb", Script: "Latn"},
+	{Name: "VietnameseCentral", Dir: "vietnamese/central", Display: "Vietnamese (Central)", ISO6391: "vi", ISO6393: "vie", Script: "Latn", Region: "VN", Variant: "central"},
+	{Name: "VietnameseNorthern", Dir: "vietnamese/northern", Display: "Vietnamese (Northern)", ISO6391: "vi", ISO6393: "vie", Script: "Latn", Region: "VN", Variant: "north", Default: true},
+	{Name: "VietnameseSouthern", Dir: "vietnamese/southern", Display: "Vietnamese (Southern)", ISO6391: "vi", ISO6393: "vie", Script: "Latn", Region: "VN", Variant: "south"},
+	{Name: "WelshNorth", Dir: "welsh/north", Display: "Welsh (North)", ISO6391: "cy", ISO6393: "cym", Script: "Latn", Variant: "north", Default: true},
+	{Name: "WelshSouth", Dir: "welsh/south", Display: "Welsh (South)", ISO6391: "cy", ISO6393: "cym", Script: "Latn", Variant: "south"},
+	{Name: "Yoruba", Dir: "yoruba", Display: "Yoruba", ISO6391: "yo", ISO6393: "yor", Script: "Latn"},
+	{Name: "Zulu", Dir: "zulu", Display: "Zulu", ISO6391: "zu", ISO6393: "zul", Script: "Latn"},
+}
@@ -1,6 +1,18 @@
 package konkani
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Konkani",
+		Dir:     "konkani",
+		Display: "Konkani",
+		ISO6393: "kok",
+		Script:  "Deva",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package kurdish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Kurdish",
+		Dir:     "kurdish",
+		Display: "Kurdish",
+		ISO6391: "ku",
+		ISO6393: "kur",
+		Script:  "Arab",
+		FS:      Language,
+	})
+}
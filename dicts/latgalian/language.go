@@ -1,6 +1,18 @@
 package latgalian
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Latgalian",
+		Dir:     "latgalian",
+		Display: "Latgalian",
+		ISO6393: "ltg",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,22 @@
 package classical
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "LatinClassical",
+		Dir:     "latin/classical",
+		Display: "Latin (Classical)",
+		ISO6391: "la",
+		ISO6393: "lat",
+		Script:  "Latn",
+		Variant: "classical",
+		Default: true,
+		Aliases: []string{"Latin"},
+		FS:      Language,
+	})
+}
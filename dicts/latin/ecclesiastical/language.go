@@ -1,6 +1,20 @@
 package ecclesiastical
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "LatinEcclesiastical",
+		Dir:     "latin/ecclesiastical",
+		Display: "Latin (Ecclesiastical)",
+		ISO6391: "la",
+		ISO6393: "lat",
+		Script:  "Latn",
+		Variant: "ecclesiastical",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package latvian
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Latvian",
+		Dir:     "latvian",
+		Display: "Latvian",
+		ISO6391: "lv",
+		ISO6393: "lav",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,18 @@
 package lojban
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Lojban",
+		Dir:     "lojban",
+		Display: "Lojban",
+		ISO6393: "jbo",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
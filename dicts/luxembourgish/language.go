@@ -1,6 +1,19 @@
 package luxembourgish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Luxembourgish",
+		Dir:     "luxembourgish",
+		Display: "Luxembourgish",
+		ISO6391: "lb",
+		ISO6393: "ltz",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
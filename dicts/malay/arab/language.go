@@ -1,6 +1,19 @@
 package arab
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "MalayArab",
+		Dir:     "malay/arab",
+		Display: "Malay (Jawi)",
+		ISO6391: "ms",
+		ISO6393: "msa",
+		Script:  "Arab",
+		FS:      Language,
+	})
+}
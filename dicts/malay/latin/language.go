@@ -1,6 +1,21 @@
 package latin
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "MalayLatin",
+		Dir:     "malay/latin",
+		Display: "Malay (Latin)",
+		ISO6391: "ms",
+		ISO6393: "msa",
+		Script:  "Latn",
+		Default: true,
+		Aliases: []string{"Malay"},
+		FS:      Language,
+	})
+}
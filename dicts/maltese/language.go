@@ -1,6 +1,19 @@
 package maltese
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Maltese",
+		Dir:     "maltese",
+		Display: "Maltese",
+		ISO6391: "mt",
+		ISO6393: "mlt",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
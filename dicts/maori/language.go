@@ -1,6 +1,19 @@
 package maori
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Maori",
+		Dir:     "maori",
+		Display: "Maori",
+		ISO6391: "mi",
+		ISO6393: "mri",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
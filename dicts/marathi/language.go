@@ -1,6 +1,19 @@
 package marathi
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Marathi",
+		Dir:     "marathi",
+		Display: "Marathi",
+		ISO6391: "mr",
+		ISO6393: "mar",
+		Script:  "Deva",
+		FS:      Language,
+	})
+}
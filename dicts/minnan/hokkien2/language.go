@@ -1,6 +1,21 @@
 package hokkien2
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "MinnanHokkien2",
+		Dir:     "minnan/hokkien2",
+		Display: "Min Nan (Hokkien)",
+		ISO6393: "nan",
+		Script:  "Hant",
+		Variant: "hokkien",
+		Default: true,
+		Aliases: []string{"MinnanHokkien"},
+		FS:      Language,
+	})
+}
@@ -1,6 +1,21 @@
 package taiwanese2
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "MinnanTaiwanese2",
+		Dir:     "minnan/taiwanese2",
+		Display: "Min Nan (Taiwanese)",
+		ISO6393: "nan",
+		Script:  "Hant",
+		Region:  "TW",
+		Variant: "taiwanese",
+		Aliases: []string{"MinnanTaiwanese"},
+		FS:      Language,
+	})
+}
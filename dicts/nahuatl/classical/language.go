@@ -1,6 +1,18 @@
 package classical
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "NahuatlClassical",
+		Dir:     "nahuatl/classical",
+		Display: "Nahuatl (Classical)",
+		ISO6393: "nci",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package nepali
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Nepali",
+		Dir:     "nepali",
+		Display: "Nepali",
+		ISO6391: "ne",
+		ISO6393: "nep",
+		Script:  "Deva",
+		FS:      Language,
+	})
+}
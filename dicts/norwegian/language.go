@@ -1,6 +1,20 @@
 package norwegian
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Norwegian",
+		Dir:     "norwegian",
+		Display: "Norwegian",
+		ISO6391: "no",
+		ISO6393: "nor",
+		Script:  "Latn",
+		Aliases: []string{"nb"},
+		FS:      Language,
+	})
+}
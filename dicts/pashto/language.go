@@ -1,6 +1,19 @@
 package pashto
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Pashto",
+		Dir:     "pashto",
+		Display: "Pashto",
+		ISO6391: "ps",
+		ISO6393: "pus",
+		Script:  "Arab",
+		FS:      Language,
+	})
+}
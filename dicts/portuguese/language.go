@@ -1,6 +1,19 @@
 package portuguese
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Portuguese",
+		Dir:     "portuguese",
+		Display: "Portuguese",
+		ISO6391: "pt",
+		ISO6393: "por",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,20 @@
 package setswana
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Setswana",
+		Dir:     "setswana",
+		Display: "Setswana",
+		ISO6391: "tn",
+		ISO6393: "tsn",
+		Script:  "Latn",
+		Aliases: []string{"Tswana"},
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package sindhi
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Sindhi",
+		Dir:     "sindhi",
+		Display: "Sindhi",
+		ISO6391: "sd",
+		ISO6393: "snd",
+		Script:  "Arab",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package slovenian
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Slovenian",
+		Dir:     "slovenian",
+		Display: "Slovenian",
+		ISO6391: "sl",
+		ISO6393: "slv",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package swedish
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Swedish",
+		Dir:     "swedish",
+		Display: "Swedish",
+		ISO6391: "sv",
+		ISO6393: "swe",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,19 @@
 package uzbek
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "Uzbek",
+		Dir:     "uzbek",
+		Display: "Uzbek",
+		ISO6391: "uz",
+		ISO6393: "uzb",
+		Script:  "Latn",
+		FS:      Language,
+	})
+}
@@ -1,6 +1,22 @@
 package northern
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "VietnameseNorthern",
+		Dir:     "vietnamese/northern",
+		Display: "Vietnamese (Northern)",
+		ISO6391: "vi",
+		ISO6393: "vie",
+		Script:  "Latn",
+		Region:  "VN",
+		Variant: "north",
+		Default: true,
+		FS:      Language,
+	})
+}
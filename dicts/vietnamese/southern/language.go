@@ -1,6 +1,21 @@
 package southern
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "VietnameseSouthern",
+		Dir:     "vietnamese/southern",
+		Display: "Vietnamese (Southern)",
+		ISO6391: "vi",
+		ISO6393: "vie",
+		Script:  "Latn",
+		Region:  "VN",
+		Variant: "south",
+		FS:      Language,
+	})
+}
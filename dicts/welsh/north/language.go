@@ -1,6 +1,21 @@
 package north
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "WelshNorth",
+		Dir:     "welsh/north",
+		Display: "Welsh (North)",
+		ISO6391: "cy",
+		ISO6393: "cym",
+		Script:  "Latn",
+		Variant: "north",
+		Default: true,
+		FS:      Language,
+	})
+}
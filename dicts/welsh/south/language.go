@@ -1,6 +1,20 @@
 package south
 
 import "embed"
+import "github.com/neurlang/goruut/pkg/registry"
 
-//go:embed missing* language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
+//go:embed manifest.json missing*.tsv lexicon.zlib language.json weights*.json.zlib language_reverse.json weights*_reverse.json.zlib
 var Language embed.FS
+
+func init() {
+	registry.Register(registry.Language{
+		Name:    "WelshSouth",
+		Dir:     "welsh/south",
+		Display: "Welsh (South)",
+		ISO6391: "cy",
+		ISO6393: "cym",
+		Script:  "Latn",
+		Variant: "south",
+		FS:      Language,
+	})
+}
@@ -6,13 +6,19 @@ import (
 	. "github.com/martinarisk/di/dependency_injection"
 )
 import "github.com/neurlang/goruut/dicts"
+import "github.com/neurlang/goruut/pkg/registry"
 import "github.com/neurlang/goruut/usecases"
 import "github.com/neurlang/goruut/models/requests"
 import "github.com/neurlang/goruut/models/responses"
 import "github.com/neurlang/goruut/repo/interfaces"
+import "github.com/neurlang/goruut/repo/models"
+
+// PipelineStages lists the pipeline stages to disable, see Phonemizer.Disable.
+type PipelineStages = models.PipelineStages
 
 type Phonemizer struct {
-	uc usecases.IPhonemizeUsecase
+	uc      usecases.IPhonemizeUsecase
+	disable PipelineStages
 }
 
 type dummy struct {
@@ -39,7 +45,20 @@ func NewPhonemizer(di *DependencyInjection) *Phonemizer {
 	}
 }
 
+// Languages lists the languages compiled in, see the goruut_subset build tag.
+func Languages() []registry.Language {
+	return registry.All()
+}
+
+// Disable skips the pipeline stages for every sentence, in addition to the stages
+// disabled by the request. It returns the phonemizer for chaining.
+func (p *Phonemizer) Disable(stages PipelineStages) *Phonemizer {
+	p.disable = stages
+	return p
+}
+
 // Sentence runs the algorithm on a sentence string in a specific language.
 func (p *Phonemizer) Sentence(r requests.PhonemizeSentence) responses.PhonemizeSentence {
+	r.Disable = r.Disable.Or(p.disable)
 	return p.uc.Sentence(r)
 }
@@ -13,3 +13,74 @@ func TestOne(t *testing.T) {
 		println(resp.Words[i].Phonetic)
 	}
 }
+
+// TestHomographModelOrder checks the words of TestOne, the homograph model tries the
+// candidates in the order it was trained with before the selection policy ranks them.
+func TestHomographModelOrder(t *testing.T) {
+	p := NewPhonemizer(nil)
+	resp := p.Sentence(requests.PhonemizeSentence{
+		Sentence: "hello world",
+		Language: "English",
+	})
+	var want = []string{"həlˈoʊ", "wˈɚld"}
+	if len(resp.Words) != len(want) {
+		t.Fatalf("got %d words, want %d", len(resp.Words), len(want))
+	}
+	for i := range want {
+		if resp.Words[i].Phonetic != want[i] {
+			t.Errorf("word %s: got %s, want %s", resp.Words[i].CleanWord, resp.Words[i].Phonetic, want[i])
+		}
+	}
+}
+
+// BenchmarkSentenceParallel phonemizes by the model from every goroutine while the other
+// languages are being loaded, the loading should not hold up the phonemization.
+func BenchmarkSentenceParallel(b *testing.B) {
+	p := NewPhonemizer(nil).Disable(PipelineStages{Lexicon: true, Cache: true})
+	var request = requests.PhonemizeSentence{
+		Sentence: "the quick brown fox jumps over the lazy dog",
+		Language: "English",
+	}
+	p.Sentence(request)
+	var stop = make(chan struct{})
+	go func() {
+		for _, lang := range []string{"Czech", "German", "French", "Spanish", "Italian", "Polish", "Dutch", "Swedish"} {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			p.Sentence(requests.PhonemizeSentence{Sentence: "hello", Language: lang})
+		}
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p.Sentence(request)
+		}
+	})
+	close(stop)
+}
+
+// TestFlapping checks the cross-word flapping of American English, also before a
+// stressed vowel.
+func TestFlapping(t *testing.T) {
+	p := NewPhonemizer(nil)
+	for sentence, want := range map[string][]string{
+		"get up":   {"gɛɾ", "ˌʌp"},
+		"at all":   {"æɾ", "ˈɔl"},
+		"get lost": {"gɛt", "lˈɔst"},
+	} {
+		resp := p.Sentence(requests.PhonemizeSentence{
+			Sentence: sentence,
+			Language: "EnglishAmerican",
+		})
+		var got []string
+		for _, word := range resp.Words {
+			got = append(got, word.Phonetic)
+		}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("%s: got %v, want %v", sentence, got, want)
+		}
+	}
+}
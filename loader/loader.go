@@ -1,41 +1,134 @@
 // Package loader implements an external language model loader based on zip files
+// and directories.
+//
+// A file found in the zip file or the directory of a language wins over the file
+// embedded in dicts, files missing there fall back to dicts. Watch polls the files
+// and tells the listeners to load a changed language again.
+//
+// A zip file or a directory holding a manifest.json is verified against it, on the
+// start and on each change. A package failing the verification is not used, the
+// language falls back to dicts until the package is fixed. A package without a
+// manifest is used unverified and serves no manifest, so its models are not checked
+// against the manifest in dicts.
 package loader
 
 import (
 	"archive/zip"
+	"errors"
+	"fmt"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/manifest"
+	"github.com/neurlang/goruut/pkg/registry"
 	"github.com/neurlang/goruut/repo/interfaces"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 import . "github.com/martinarisk/di/dependency_injection"
 
 type ILoader interface {
 	interfaces.DictGetter
+	interfaces.ReloadNotifier
+}
+
+// source is the zip file or the directory overlaying the files of a language.
+type source struct {
+	path string
+	dir  bool
+	// zip is opened once on the first read and closed when the file changes.
+	zip *zip.ReadCloser
+	// stamp sums up the sizes and the modification times of the files.
+	stamp string
+	// invalid is set when the files do not match the manifest.
+	invalid bool
+	// manifest is set when the source has a manifest.
+	manifest bool
 }
 
 type Loader struct {
 	g interfaces.DictGetter
-	d *map[string]string
+	d *map[string]*source
+
+	mut       *sync.RWMutex
+	listeners *[]func(lang string)
 }
 
 func (l *Loader) GetDict(lang, file string) ([]byte, error) {
-	if zipfile, ok := (*l.d)[lang]; ok {
-		reader := log.Error1(zip.OpenReader(zipfile))
-		if reader != nil {
-			f := log.Error1(reader.Open(file))
-			if f != nil {
-				data := log.Error1(io.ReadAll(f))
-				if len(data) > 0 {
-					log.Now().Infof("Loader used file: %s %s %s", lang, zipfile, file)
-					return data, nil
-				}
-			}
+	lang = registry.Canonical(lang)
+	l.mut.RLock()
+	src := (*l.d)[lang]
+	var use = src != nil && !src.invalid
+	var ownManifest = use && src.manifest
+	l.mut.RUnlock()
+	if use {
+		if file == manifest.FileName && !ownManifest {
+			return nil, nil
+		}
+		if data := l.read(src, file); len(data) > 0 {
+			log.Now().Infof("Loader used file: %s %s %s", lang, src.path, file)
+			return data, nil
 		}
 	}
 
 	return l.g.GetDict(lang, file)
 }
+
+// read returns the file of the source, nil when the source does not have it.
+func (l *Loader) read(src *source, file string) []byte {
+	var data []byte
+	var err error
+	if src.dir {
+		data, err = os.ReadFile(filepath.Join(src.path, filepath.FromSlash(file)))
+	} else {
+		data, err = l.readZip(src, file)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Error0(err)
+	}
+	return data
+}
+
+// readZip reads the file from the zip of the source, opening the zip on first use. The
+// read lock is held while reading, a reload must not close the zip in the middle.
+func (l *Loader) readZip(src *source, file string) ([]byte, error) {
+	l.mut.RLock()
+	for src.zip == nil {
+		l.mut.RUnlock()
+		if err := l.openZip(src); err != nil {
+			return nil, err
+		}
+		l.mut.RLock()
+	}
+	defer l.mut.RUnlock()
+
+	f, err := src.zip.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// openZip opens the zip of the source unless it is open already.
+func (l *Loader) openZip(src *source) error {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	if src.zip != nil {
+		return nil
+	}
+	reader, err := zip.OpenReader(src.path)
+	if err != nil {
+		return err
+	}
+	src.zip = reader
+	return nil
+}
+
 func (l *Loader) IsNewFormat(data []byte) bool {
 	return l.g.IsNewFormat(data)
 }
@@ -43,10 +136,130 @@ func (l *Loader) IsOldFormat(data []byte) bool {
 	return l.g.IsNewFormat(data)
 }
 
+// OnReload adds a listener called with the language whose files changed.
+func (l *Loader) OnReload(listener func(lang string)) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	*l.listeners = append(*l.listeners, listener)
+}
+
+// verify checks the files of the source against its manifest, if any.
+func verify(src *source) (hasManifest bool, err error) {
+	var fsys fs.FS
+	if src.dir {
+		fsys = os.DirFS(src.path)
+	} else {
+		reader, err := zip.OpenReader(src.path)
+		if err != nil {
+			return false, err
+		}
+		defer reader.Close()
+		fsys = reader
+	}
+	data, err := fs.ReadFile(fsys, manifest.FileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return true, err
+	}
+	return true, m.VerifyFS(fsys, true)
+}
+
+// check verifies the source and logs the outcome.
+func check(lang string, src *source) {
+	var err error
+	src.manifest, err = verify(src)
+	src.invalid = err != nil
+	switch {
+	case err != nil:
+		log.Now().Errorf("Loader not using %s for language %s: %v", src.path, lang, err)
+	case !src.manifest:
+		log.Now().Warningf("Loader using %s for language %s without a manifest, the files are not verified", src.path, lang)
+	}
+}
+
+// stamp sums up the files of the source, the zip file itself or the files in the directory.
+func stamp(src *source) (string, error) {
+	if !src.dir {
+		fi, err := os.Stat(src.path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprint(fi.Size(), fi.ModTime().UnixNano()), nil
+	}
+	entries, err := os.ReadDir(src.path)
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	for _, entry := range entries {
+		fi, err := entry.Info()
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		files = append(files, fmt.Sprint(entry.Name(), fi.Size(), fi.ModTime().UnixNano()))
+	}
+	sort.Strings(files)
+	return strings.Join(files, "\n"), nil
+}
+
+// Watch polls the files of the languages every interval, in the manner of watchFile in
+// cmd/backtest, until stop is closed. A changed zip file is opened again and the
+// listeners are told to forget the language.
+func (l *Loader) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, lang := range l.changed() {
+			log.Now().Infof("Loader reloading language %s", lang)
+			l.mut.RLock()
+			listeners := *l.listeners
+			l.mut.RUnlock()
+			for _, listener := range listeners {
+				listener(lang)
+			}
+		}
+	}
+}
+
+// changed returns the languages whose files changed since the last call.
+func (l *Loader) changed() (langs []string) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	for lang, src := range *l.d {
+		now, err := stamp(src)
+		if err != nil {
+			// the file is being replaced, try again on the next tick
+			continue
+		}
+		if now == src.stamp {
+			continue
+		}
+		src.stamp = now
+		if src.zip != nil {
+			log.Error0(src.zip.Close())
+			src.zip = nil
+		}
+		check(lang, src)
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return
+}
+
 func NewLoader(di *DependencyInjection) *Loader {
 	getter := MustAny[interfaces.DictGetter](di)
 	loadModels := MustAny[interfaces.LoadModels](di)
-	data := make(map[string]string)
+	data := make(map[string]*source)
 
 	for _, m := range loadModels.GetLoadModels() {
 		if m == nil {
@@ -58,23 +271,42 @@ func NewLoader(di *DependencyInjection) *Loader {
 		if m.File == "" {
 			continue
 		}
-		if m.Size != 0 {
-			fi := log.Error1(os.Stat(m.File))
+		fi := log.Error1(os.Stat(m.File))
+		if fi == nil {
+			continue
+		}
+		if m.Size != 0 && !fi.IsDir() {
 			realSize := fi.Size()
 			if realSize != m.Size {
 				continue
 			}
 		}
-		data[m.Lang] = m.File
+		src := &source{
+			path: m.File,
+			dir:  fi.IsDir(),
+		}
+		src.stamp, _ = stamp(src)
+		check(m.Lang, src)
+		data[registry.Canonical(m.Lang)] = src
 		log.Now().Infof("Loaded language %s as %s", m.Lang, m.File)
 	}
 	// remove the previous getter
 	di.Remove(getter)
 
-	return &Loader{
-		g: getter,
-		d: &data,
+	var listeners []func(lang string)
+	loader := &Loader{
+		g:         getter,
+		d:         &data,
+		mut:       &sync.RWMutex{},
+		listeners: &listeners,
 	}
+
+	var watch interfaces.WatchModels
+	if Any(di, &watch) == nil && watch.GetWatchModels() > 0 {
+		go loader.Watch(watch.GetWatchModels(), nil)
+	}
+
+	return loader
 }
 
 var _ ILoader = &Loader{}
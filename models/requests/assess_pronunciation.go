@@ -0,0 +1,9 @@
+package requests
+
+type AssessPronunciation struct {
+	IpaFlavors []string
+	Language   string
+	Languages  []string
+	Sentence   string
+	Heard      string
+}
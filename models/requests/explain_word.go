@@ -1,9 +1,18 @@
 // Package requests contains API request payload models.
 package requests
 
+import "github.com/neurlang/goruut/pkg/registry"
+
 type ExplainWord struct {
 	Language  string
+	Languages []string `json:",omitempty"`
 	CleanWord string
 	Phonetic  string
 	IsReverse bool
 }
+
+// Init turns BCP-47 tags and aliases into the names of the languages.
+func (p *ExplainWord) Init() {
+	p.Language = registry.Canonical(p.Language)
+	p.Languages = canonical(p.Languages)
+}
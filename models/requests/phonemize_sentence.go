@@ -1,5 +1,8 @@
 package requests
 
+import "github.com/neurlang/goruut/pkg/registry"
+import "github.com/neurlang/goruut/repo/models"
+
 type PhonemizeSentence struct {
 	IpaFlavors []string
 	Language   string
@@ -8,10 +11,41 @@ type PhonemizeSentence struct {
 	IsReverse  bool
 
 	SplitSentences bool
+
+	SymbolTable string `json:",omitempty"`
+	Alignment   bool   `json:",omitempty"`
+
+	// PosHints maps the index of a word, counted over the words split from the
+	// whole sentence, to part of speech tags or UD features such as "Tense=Past".
+	PosHints map[int][]string `json:",omitempty"`
+	// PosMarkup enables hints written inline in braces after a word, as in "read{VERB|Tense=Past}".
+	PosMarkup bool `json:",omitempty"`
+
+	// PunctuationTokens emits every punctuation mark as a token of its own, classified
+	// and with a suggested break strength, next to the words it is attached to.
+	PunctuationTokens bool `json:",omitempty"`
+
+	// Disable lists the pipeline stages to skip, for debugging and evaluation.
+	Disable models.PipelineStages
 }
 
+// Init defaults the language to the first of the languages and turns BCP-47 tags
+// and aliases into the names of the languages.
 func (p *PhonemizeSentence) Init() {
 	if p.Language == "" && len(p.Languages) > 0 {
 		p.Language = p.Languages[0]
 	}
+	p.Language = registry.Canonical(p.Language)
+	p.Languages = canonical(p.Languages)
+}
+
+func canonical(languages []string) []string {
+	if languages == nil {
+		return nil
+	}
+	var ret = make([]string, len(languages))
+	for i, lang := range languages {
+		ret[i] = registry.Canonical(lang)
+	}
+	return ret
 }
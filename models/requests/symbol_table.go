@@ -0,0 +1,8 @@
+package requests
+
+import "github.com/neurlang/goruut/pkg/symboltable"
+
+type SymbolTable struct {
+	Name  string
+	Table *symboltable.Table
+}
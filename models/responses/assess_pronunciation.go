@@ -0,0 +1,16 @@
+package responses
+
+import "github.com/neurlang/goruut/repo/models"
+
+type AssessPronunciation struct {
+	Words []AssessPronunciationWord
+	Score float64
+
+	ErrorWordLimitExceeded   bool   `json:"ErrorWordLimitExceeded,omitempty"`
+	ErrorUnsupportedLanguage string `json:"ErrorUnsupportedLanguage,omitempty"`
+}
+
+type AssessPronunciationWord struct {
+	CleanWord string
+	models.AssessedWord
+}
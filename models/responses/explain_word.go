@@ -1,6 +1,15 @@
 // Package responses contains API response payload models.
 package responses
 
+import "github.com/neurlang/goruut/repo/models"
+
 type ExplainWord struct {
 	Rules map[string][]string
+
+	Trace      *models.DecodeTrace    `json:"Trace,omitempty"`
+	Divergence *models.Divergence     `json:"Divergence,omitempty"`
+	Lexicon    []models.LexiconEntry  `json:"Lexicon,omitempty"`
+	Homograph  []models.HomographVote `json:"Homograph,omitempty"`
+
+	ErrorUnsupportedLanguage string `json:"ErrorUnsupportedLanguage,omitempty"`
 }
@@ -0,0 +1,7 @@
+package responses
+
+import "github.com/neurlang/goruut/pkg/registry"
+
+type Languages struct {
+	Languages []registry.Language
+}
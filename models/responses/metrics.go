@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/neurlang/goruut/pkg/wordcache"
+
+// Metrics are the counters of the server.
+type Metrics struct {
+	// WordCache are the counters of the word cache by its tier, "memory" and "disk".
+	WordCache map[string]wordcache.Stats
+}
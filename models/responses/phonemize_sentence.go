@@ -1,11 +1,20 @@
 package responses
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"github.com/neurlang/goruut/repo/models"
+)
 
 type PhonemizeSentence struct {
 	Words []PhonemizeSentenceWord
 
-	ErrorWordLimitExceeded bool `json:"ErrorWordLimitExceeded,omitempty"`
+	PhonemeIds []int `json:"PhonemeIds,omitempty"`
+
+	ErrorWordLimitExceeded  bool `json:"ErrorWordLimitExceeded,omitempty"`
+	ErrorUnknownSymbolTable bool `json:"ErrorUnknownSymbolTable,omitempty"`
+
+	// ErrorUnsupportedLanguage explains why the language, or one of the fallback languages, cannot be used.
+	ErrorUnsupportedLanguage string `json:"ErrorUnsupportedLanguage,omitempty"`
 }
 
 func (p *PhonemizeSentence) Init() {
@@ -23,4 +32,22 @@ type PhonemizeSentenceWord struct {
 
 	IsFirst bool
 	IsLast  bool
+
+	PhoneTokens   []string       `json:"PhoneTokens,omitempty"`
+	PhoneIds      []int          `json:"PhoneIds,omitempty"`
+	MissingPhones []MissingPhone `json:"MissingPhones,omitempty"`
+
+	Alignment [][2]string `json:"Alignment,omitempty"`
+
+	CrossWordRules []string `json:"CrossWordRules,omitempty"`
+
+	// Punctuation is set on the punctuation tokens, whose CleanWord is the mark.
+	Punctuation *models.Punctuation `json:"Punctuation,omitempty"`
+
+	models.CleanStatus
+}
+
+type MissingPhone struct {
+	Phone      string
+	Suggestion string
 }
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/neurlang/goruut/pkg/residency"
+
+type Residency struct {
+	residency.Report
+	// Enabled is false when the server does not track the memory of the languages.
+	Enabled bool
+}
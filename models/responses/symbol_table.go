@@ -0,0 +1,7 @@
+package responses
+
+type SymbolTable struct {
+	Names []string
+
+	Error string `json:"Error,omitempty"`
+}
@@ -0,0 +1,149 @@
+// Package crossword rewrites pronunciations at word boundaries, such as French
+// liaison, Mandarin third tone sandhi, synalepha or English flapping.
+//
+// A rule matches two adjacent words. Each side may require the spelling of the
+// word, one of its tags and a regular expression on its pronunciation, which for
+// the left word is usually anchored at the end ("t$") and for the right word at
+// the start ("^ˈ?[aeiou]"). A rule which matched rewrites the matched part of the
+// pronunciation of either side, "$1" and the like expand to the submatches.
+//
+// Punctuation between the words blocks a rule: by default any punctuation does,
+// a rule listing its Barriers is blocked only by those characters.
+package crossword
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Side matches one of the two words at a boundary, empty fields match any word.
+type Side struct {
+	// Words lists the spellings of the word.
+	Words []string `json:",omitempty"`
+	// Tags lists the tags, the word needs one of them.
+	Tags []string `json:",omitempty"`
+	// Phonetic is a regular expression the pronunciation needs to match.
+	Phonetic string `json:",omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Rule is one cross-word rule.
+type Rule struct {
+	Name  string
+	Left  Side
+	Right Side
+	// Barriers lists the punctuation characters blocking the rule, nil means any punctuation.
+	Barriers *string `json:",omitempty"`
+	// LeftTo replaces the part of the left pronunciation matched by Left.Phonetic, nil keeps it.
+	LeftTo *string `json:",omitempty"`
+	// RightTo replaces the part of the right pronunciation matched by Right.Phonetic, nil keeps it.
+	RightTo *string `json:",omitempty"`
+}
+
+// Word is one word of the selected sequence.
+type Word struct {
+	Word      string
+	Phonetic  string
+	Tags      []string
+	PrePunct  string
+	PostPunct string
+	// Rules lists the names of the rules which fired at either boundary of the word.
+	Rules []string
+}
+
+// Compile checks the rule and compiles its expressions.
+func (r *Rule) Compile() (err error) {
+	if r.Name == "" {
+		return fmt.Errorf("cross-word rule without a name")
+	}
+	if r.Left.re, err = compile(r.Left.Phonetic); err != nil {
+		return fmt.Errorf("cross-word rule %s: %w", r.Name, err)
+	}
+	if r.Right.re, err = compile(r.Right.Phonetic); err != nil {
+		return fmt.Errorf("cross-word rule %s: %w", r.Name, err)
+	}
+	if r.LeftTo != nil && r.Left.re == nil || r.RightTo != nil && r.Right.re == nil {
+		return fmt.Errorf("cross-word rule %s rewrites a side without a phonetic pattern", r.Name)
+	}
+	if r.LeftTo == nil && r.RightTo == nil {
+		return fmt.Errorf("cross-word rule %s rewrites nothing", r.Name)
+	}
+	return nil
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+func (s *Side) match(w *Word) bool {
+	if len(s.Words) > 0 && !contains(s.Words, w.Word) {
+		return false
+	}
+	if len(s.Tags) > 0 {
+		var found bool
+		for _, tag := range w.Tags {
+			found = found || contains(s.Tags, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	return s.re == nil || s.re.MatchString(w.Phonetic)
+}
+
+func (s *Side) rewrite(w *Word, to *string) {
+	if to != nil {
+		w.Phonetic = s.re.ReplaceAllString(w.Phonetic, *to)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, one := range list {
+		if one == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) blocked(punct string) bool {
+	punct = strings.TrimFunc(punct, unicode.IsSpace)
+	if r.Barriers == nil {
+		return punct != ""
+	}
+	return strings.ContainsAny(punct, *r.Barriers)
+}
+
+// Match reports whether the rule applies at the boundary between the words.
+func (r *Rule) Match(left, right *Word) bool {
+	if r.blocked(left.PostPunct + right.PrePunct) {
+		return false
+	}
+	return r.Left.match(left) && r.Right.match(right)
+}
+
+// Apply walks the boundaries of the words from left to right and fires the first
+// matching compiled rule at each of them. A word rewritten at its left boundary is
+// matched in its rewritten form at its right boundary.
+func Apply(rules []Rule, words []Word) {
+	for i := 0; i+1 < len(words); i++ {
+		left, right := &words[i], &words[i+1]
+		for j := range rules {
+			rule := &rules[j]
+			if !rule.Match(left, right) {
+				continue
+			}
+			rule.Left.rewrite(left, rule.LeftTo)
+			rule.Right.rewrite(right, rule.RightTo)
+			left.Rules = append(left.Rules, rule.Name)
+			right.Rules = append(right.Rules, rule.Name)
+			break
+		}
+	}
+}
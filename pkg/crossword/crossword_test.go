@@ -0,0 +1,107 @@
+package crossword
+
+import (
+	"reflect"
+	"testing"
+)
+
+func str(s string) *string {
+	return &s
+}
+
+func phonetics(words []Word) (ret []string) {
+	for _, w := range words {
+		ret = append(ret, w.Phonetic)
+	}
+	return
+}
+
+func compiled(t *testing.T, rules ...Rule) []Rule {
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rules
+}
+
+func TestLiaison(t *testing.T) {
+	rules := compiled(t, Rule{
+		Name:   "liaison-z",
+		Left:   Side{Words: []string{"les"}, Phonetic: "e$"},
+		Right:  Side{Phonetic: "^ˈ?[aeiouɔɛ]"},
+		LeftTo: str("ez"),
+	})
+	words := []Word{
+		{Word: "les", Phonetic: "le"},
+		{Word: "amis", Phonetic: "amˈi"},
+		{Word: "les", Phonetic: "le"},
+		{Word: "chats", Phonetic: "ʃa"},
+	}
+	Apply(rules, words)
+	want := []string{"lez", "amˈi", "le", "ʃa"}
+	if got := phonetics(words); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(words[1].Rules, []string{"liaison-z"}) || words[3].Rules != nil {
+		t.Fatalf("unexpected rules fired: %v", words)
+	}
+}
+
+func TestToneSandhi(t *testing.T) {
+	rules := compiled(t, Rule{
+		Name:   "third-tone",
+		Left:   Side{Phonetic: "˨˩˦$"},
+		Right:  Side{Phonetic: "^[^˥˦˧˨˩]*˨˩˦"},
+		LeftTo: str("˧˥"),
+	})
+	words := []Word{
+		{Phonetic: "ni˨˩˦"},
+		{Phonetic: "xən˨˩˦"},
+		{Phonetic: "xɑʊ˨˩˦"},
+	}
+	Apply(rules, words)
+	want := []string{"ni˧˥", "xən˧˥", "xɑʊ˨˩˦"}
+	if got := phonetics(words); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBarriersAndTags(t *testing.T) {
+	rules := compiled(t, Rule{
+		Name:     "flap",
+		Left:     Side{Tags: []string{"verb"}, Phonetic: "([aeiouæɛɪʊʌ])t$"},
+		Right:    Side{Phonetic: "^ˈ?[aeiouæɛɪʊʌ]"},
+		Barriers: str(".,;"),
+		LeftTo:   str("${1}ɾ"),
+	})
+	words := []Word{
+		{Phonetic: "gɛt", Tags: []string{"verb"}, PostPunct: "-"},
+		{Phonetic: "ɪt"},
+		{Phonetic: "kæt", PostPunct: ","},
+		{Phonetic: "ɪz"},
+	}
+	Apply(rules, words)
+	want := []string{"gɛɾ", "ɪt", "kæt", "ɪz"}
+	if got := phonetics(words); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	words[2].Tags = []string{"verb"}
+	Apply(rules, words[2:])
+	if words[2].Phonetic != "kæt" {
+		t.Fatalf("rule crossed a barrier: %v", words[2])
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, rule := range []Rule{
+		{Left: Side{Phonetic: "a$"}, LeftTo: str("b")},
+		{Name: "bad", Left: Side{Phonetic: "("}, LeftTo: str("b")},
+		{Name: "blind", RightTo: str("b")},
+		{Name: "noop", Left: Side{Phonetic: "a$"}},
+	} {
+		if rule.Compile() == nil {
+			t.Fatalf("expected an error for %v", rule)
+		}
+	}
+}
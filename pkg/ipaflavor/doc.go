@@ -0,0 +1,16 @@
+// Package ipaflavor compiles IPA flavor rewrite rules into a deterministic
+// longest-match trie and applies them to phonetic strings.
+//
+// A flavor is compiled once and then applied in a single left-to-right pass.
+// At each position the longest source key whose context conditions hold wins;
+// the output of one rule is never rewritten again by another rule.
+//
+// Context:
+//   - Initial and Final anchor a rule to the start or end of the word. The
+//     word separators "_" and " " count as word boundaries too.
+//   - Left and Right name a phone class that must precede or follow the
+//     matched source string. A leading "!" negates the class.
+//
+// Among rules sharing the same source key, the one with more context
+// conditions is tried first, ties are resolved by declaration order.
+package ipaflavor
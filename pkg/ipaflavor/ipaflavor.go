@@ -0,0 +1,262 @@
+package ipaflavor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule rewrites the source string From to To, optionally only in context.
+type Rule struct {
+	From    string
+	To      string
+	Initial bool   `json:",omitempty"`
+	Final   bool   `json:",omitempty"`
+	Left    string `json:",omitempty"`
+	Right   string `json:",omitempty"`
+}
+
+// specificity counts the context conditions of the rule.
+func (r *Rule) specificity() (n int) {
+	if r.Initial {
+		n++
+	}
+	if r.Final {
+		n++
+	}
+	if r.Left != "" {
+		n++
+	}
+	if r.Right != "" {
+		n++
+	}
+	return
+}
+
+// sameContext reports whether both rules fire in exactly the same context.
+func (r *Rule) sameContext(o *Rule) bool {
+	return r.Initial == o.Initial && r.Final == o.Final && r.Left == o.Left && r.Right == o.Right
+}
+
+// FromMap converts a plain source to destination mapping into context free rules,
+// ordered by source string so that compilation is deterministic.
+func FromMap(mapping map[string]string) (rules []Rule) {
+	for from, to := range mapping {
+		rules = append(rules, Rule{From: from, To: to})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].From < rules[j].From
+	})
+	return
+}
+
+type node struct {
+	next  map[rune]*node
+	rules []*Rule
+}
+
+// Flavor is a compiled, immutable set of rules safe for concurrent use.
+type Flavor struct {
+	name    string
+	root    *node
+	classes map[string][]string
+}
+
+// Name returns the flavor name the rules were compiled under.
+func (f *Flavor) Name() string {
+	return f.name
+}
+
+// Compile validates the rules and builds the trie of a flavor named name.
+// Classes maps phone class names to their member phones.
+func Compile(name string, rules []Rule, classes map[string][]string) (*Flavor, error) {
+	f := &Flavor{
+		name:    name,
+		root:    &node{},
+		classes: classes,
+	}
+	for i := range rules {
+		rule := rules[i]
+		if rule.From == "" {
+			return nil, fmt.Errorf("ipa flavor %s: rule %d has empty source string", name, i)
+		}
+		if rule.From == rule.To && rule.specificity() == 0 {
+			return nil, fmt.Errorf("ipa flavor %s: rule %d has identical source and dest string: %s", name, i, rule.From)
+		}
+		for _, class := range []string{rule.Left, rule.Right} {
+			if class == "" {
+				continue
+			}
+			if _, ok := classes[strings.TrimPrefix(class, "!")]; !ok {
+				return nil, fmt.Errorf("ipa flavor %s: rule %d (%s) references unknown phone class: %s", name, i, rule.From, class)
+			}
+		}
+		n := f.root
+		for _, r := range rule.From {
+			if n.next == nil {
+				n.next = make(map[rune]*node)
+			}
+			if n.next[r] == nil {
+				n.next[r] = &node{}
+			}
+			n = n.next[r]
+		}
+		for _, existing := range n.rules {
+			if existing.sameContext(&rule) && existing.To != rule.To {
+				return nil, fmt.Errorf("ipa flavor %s: conflicting rules for %s in the same context: %s versus %s",
+					name, rule.From, existing.To, rule.To)
+			}
+		}
+		n.rules = append(n.rules, &rule)
+	}
+	f.sort(f.root)
+	return f, nil
+}
+
+func (f *Flavor) sort(n *node) {
+	sort.SliceStable(n.rules, func(i, j int) bool {
+		return n.rules[i].specificity() > n.rules[j].specificity()
+	})
+	for _, child := range n.next {
+		f.sort(child)
+	}
+}
+
+// inClass reports whether the phone class (possibly negated) matches.
+func (f *Flavor) inClass(class string, match func(phone string) bool) bool {
+	negate := strings.HasPrefix(class, "!")
+	for _, phone := range f.classes[strings.TrimPrefix(class, "!")] {
+		if match(phone) {
+			return !negate
+		}
+	}
+	return negate
+}
+
+// isBoundary reports whether the byte is a word separator inside a phonetic string.
+func isBoundary(b byte) bool {
+	return b == '_' || b == ' '
+}
+
+func (f *Flavor) holds(rule *Rule, word string, start, end int) bool {
+	if rule.Initial && start != 0 && !isBoundary(word[start-1]) {
+		return false
+	}
+	if rule.Final && end != len(word) && !isBoundary(word[end]) {
+		return false
+	}
+	if rule.Left != "" && !f.inClass(rule.Left, func(phone string) bool {
+		return strings.HasSuffix(word[:start], phone)
+	}) {
+		return false
+	}
+	if rule.Right != "" && !f.inClass(rule.Right, func(phone string) bool {
+		return strings.HasPrefix(word[end:], phone)
+	}) {
+		return false
+	}
+	return true
+}
+
+// match returns the rule and the byte end of the longest applicable match at start.
+func (f *Flavor) match(word string, start int) (best *Rule, bestEnd int) {
+	n := f.root
+	for end := start; end < len(word); {
+		r, size := utf8.DecodeRuneInString(word[end:])
+		n = n.next[r]
+		if n == nil {
+			break
+		}
+		end += size
+		for _, rule := range n.rules {
+			if f.holds(rule, word, start, end) {
+				best, bestEnd = rule, end
+				break
+			}
+		}
+	}
+	return
+}
+
+// Apply rewrites the word in a single pass, context is evaluated on the input.
+func (f *Flavor) Apply(word string) string {
+	if f == nil || f.root == nil {
+		return word
+	}
+	var out strings.Builder
+	for i := 0; i < len(word); {
+		rule, end := f.match(word, i)
+		if rule == nil {
+			_, size := utf8.DecodeRuneInString(word[i:])
+			out.WriteString(word[i : i+size])
+			i += size
+			continue
+		}
+		out.WriteString(rule.To)
+		i = end
+	}
+	return out.String()
+}
+
+// ApplyParts rewrites the word made of the parts, such as the chunks of an alignment, in
+// a single pass as Apply does, and splits the output at the boundaries of the parts. The
+// output of a rule matching across a boundary goes to the part where the match starts.
+func (f *Flavor) ApplyParts(parts []string) []string {
+	if f == nil || f.root == nil {
+		return parts
+	}
+	word := strings.Join(parts, "")
+	out := make([]string, len(parts))
+	var part, bound int
+	if len(parts) > 0 {
+		bound = len(parts[0])
+	}
+	for i := 0; i < len(word); {
+		for part < len(parts)-1 && i >= bound {
+			part++
+			bound += len(parts[part])
+		}
+		rule, end := f.match(word, i)
+		if rule == nil {
+			_, size := utf8.DecodeRuneInString(word[i:])
+			out[part] += word[i : i+size]
+			i += size
+			continue
+		}
+		out[part] += rule.To
+		i = end
+	}
+	return out
+}
+
+// CompileAll compiles every flavor named either in the plain mappings or in the
+// contextual rules. The plain mapping of a flavor precedes its contextual rules.
+// Flavors which fail to compile are left out and their errors are joined.
+func CompileAll(mappings map[string]map[string]string, rules map[string][]Rule,
+	classes map[string][]string) (flavors map[string]*Flavor, err error) {
+
+	var names []string
+	for name := range mappings {
+		names = append(names, name)
+	}
+	for name := range rules {
+		if _, ok := mappings[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	flavors = make(map[string]*Flavor)
+	var errs []error
+	for _, name := range names {
+		flavor, err := Compile(name, append(FromMap(mappings[name]), rules[name]...), classes)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		flavors[name] = flavor
+	}
+	return flavors, errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package ipaflavor
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustCompile(t *testing.T, rules []Rule, classes map[string][]string) *Flavor {
+	t.Helper()
+	flavor, err := Compile("test", rules, classes)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return flavor
+}
+
+func TestApplyLongestMatchSinglePass(t *testing.T) {
+	flavor := mustCompile(t, FromMap(map[string]string{
+		"a":  "ʌ",
+		"au": "aʊ",
+		"ɛ":  "E",
+		"ɛ̃": "I",
+	}), nil)
+	if got, want := flavor.Apply("hauɛ̃ɛa"), "haʊIEʌ"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestApplyDeterministic(t *testing.T) {
+	mapping := map[string]string{"ab": "X", "bc": "Y", "a": "1", "b": "2", "c": "3"}
+	want := mustCompile(t, FromMap(mapping), nil).Apply("abcabc")
+	for i := 0; i < 50; i++ {
+		if got := mustCompile(t, FromMap(mapping), nil).Apply("abcabc"); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+	if want != "X3X3" {
+		t.Fatalf("expected %q, got %q", "X3X3", want)
+	}
+}
+
+func TestApplyContext(t *testing.T) {
+	classes := map[string][]string{"Vowel": {"a", "e", "i", "o", "u"}}
+	flavor := mustCompile(t, []Rule{
+		{From: "t", To: "ɾ", Left: "Vowel", Right: "Vowel"},
+		{From: "h", To: "", Initial: true},
+		{From: "d", To: "t", Final: true},
+		{From: "n", To: "ŋ", Right: "!Vowel"},
+	}, classes)
+	for _, tc := range [][2]string{
+		{"hatadh", "aɾadh"},
+		{"tat", "tat"},
+		{"bad_had", "bat_at"},
+		{"nank", "naŋk"},
+	} {
+		if got := flavor.Apply(tc[0]); got != tc[1] {
+			t.Fatalf("%s: expected %q, got %q", tc[0], tc[1], got)
+		}
+	}
+}
+
+func TestApplyParts(t *testing.T) {
+	flavor := mustCompile(t, []Rule{
+		{From: "t", To: "ʔ", Final: true},
+		{From: "tʃ", To: "C"},
+	}, nil)
+	for _, c := range []struct {
+		parts, want []string
+	}{
+		{[]string{"t", "ɛ", "s", "t"}, []string{"t", "ɛ", "s", "ʔ"}},
+		{[]string{"tɛ", "st"}, []string{"tɛ", "sʔ"}},
+		{[]string{"a", "t", "ʃ", "a"}, []string{"a", "C", "", "a"}},
+		{[]string{"", "t"}, []string{"", "ʔ"}},
+		{nil, nil},
+	} {
+		got := flavor.ApplyParts(c.parts)
+		if strings.Join(got, "|") != strings.Join(c.want, "|") || len(got) != len(c.want) {
+			t.Errorf("%q: expected %q, got %q", c.parts, c.want, got)
+		}
+		if whole := flavor.Apply(strings.Join(c.parts, "")); strings.Join(got, "") != whole {
+			t.Errorf("%q: the parts %q do not join up to %q", c.parts, got, whole)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		rules []Rule
+		err   string
+	}{
+		{[]Rule{{From: "", To: "a"}}, "empty source"},
+		{[]Rule{{From: "a", To: "a"}}, "identical source and dest"},
+		{[]Rule{{From: "a", To: "b", Left: "Nasal"}}, "unknown phone class"},
+		{[]Rule{{From: "a", To: "b", Final: true}, {From: "a", To: "c", Final: true}}, "conflicting rules"},
+	} {
+		_, err := Compile("test", tc.rules, nil)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("expected error containing %q, got %v", tc.err, err)
+		}
+	}
+}
+
+func TestCompileAllMergesRules(t *testing.T) {
+	flavors, err := CompileAll(map[string]map[string]string{
+		"Espeak": {"ə": "@"},
+		"Broken": {"x": "x"},
+	}, map[string][]Rule{
+		"Espeak": {{From: "ə", To: "", Final: true}},
+	}, nil)
+	if err == nil {
+		t.Fatalf("expected error for the broken flavor")
+	}
+	if flavors["Broken"] != nil {
+		t.Fatalf("expected the broken flavor to be left out")
+	}
+	if got, want := flavors["Espeak"].Apply("əbə"), "@b"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
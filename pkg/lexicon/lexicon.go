@@ -0,0 +1,552 @@
+// Package lexicon stores a pronunciation lexicon in a compact binary form which is
+// looked up in place, without building maps.
+//
+// The lexicon holds the pairs of a word and its pronunciation, each with its tags,
+// the position of its first record and the number of its records. The words and the
+// pronunciations share one sorted string table, front coded in blocks of 16 strings.
+// The tag sets are interned into a table of their json. The pairs are sorted by the
+// word and a second index sorts them by the pronunciation, so the same lexicon serves
+// both directions.
+//
+// Lexicons are compiled from the tsv files by a Builder, at build time by go generate
+// in dicts, and stored zlib compressed as lexicon.zlib.
+package lexicon
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/neurlang/classifier/hash"
+)
+
+// FileName is the name of the compiled lexicon of a language.
+const FileName = "lexicon.zlib"
+
+// SourceName is the name of the lexicon of a language the compiled lexicon is made of.
+const SourceName = "missing.all.zlib"
+
+var ErrFormat = errors.New("not a compiled lexicon")
+
+var magic = [4]byte{'G', 'L', 'X', '1'}
+
+// the columns of the pairs: the word, the pronunciation, the tags, the order, the
+// frequency, and the index of the pairs sorted by the pronunciation
+const (
+	colSrc = iota
+	colDst
+	colTag
+	colOrder
+	colFrequency
+	colReverse
+	columns
+)
+
+const (
+	headerSize = 4 + sha256.Size + 6*4 + columns
+	blockSize  = 16
+	tagSize    = 3 * 4
+)
+
+// Entry is a pair of the lexicon. Looked up in reverse, Src is the pronunciation and Dst the word.
+type Entry struct {
+	Src, Dst string
+	// Tags is the json array of the tags, Key the xor of their hashes.
+	Tags string
+	Key  uint32
+	// Order is the position of the first record of the pair, Frequency the number of its records.
+	Order     int
+	Frequency int
+}
+
+// Lexicon is a compiled lexicon, it reads the data in place.
+type Lexicon struct {
+	data    []byte
+	source  [sha256.Size]byte
+	records int
+
+	strings int
+	blocks  []byte
+	text    []byte
+	tags    []byte
+	tagText []byte
+	pairs   int
+	cols    [columns]column
+}
+
+// column holds a number per pair, in the fewest bytes fitting the largest one. The
+// bytes are stored in planes, the lowest bytes of all the numbers first, so that the
+// runs of the high bytes compress well.
+type column struct {
+	data  []byte
+	width int
+	n     int
+}
+
+func (c *column) get(i int) (v int) {
+	for j := c.width - 1; j >= 0; j-- {
+		v = v<<8 | int(c.data[j*c.n+i])
+	}
+	return
+}
+
+// width returns the bytes of the largest number.
+func width(values []int) (w int) {
+	for _, v := range values {
+		for v>>(8*w) != 0 {
+			w++
+		}
+	}
+	return
+}
+
+func (c *column) append(out []byte, values []int) []byte {
+	for j := 0; j < c.width; j++ {
+		for _, v := range values {
+			out = append(out, byte(v>>(8*j)))
+		}
+	}
+	return out
+}
+
+func u32(b []byte, i int) uint32 {
+	return binary.LittleEndian.Uint32(b[4*i:])
+}
+
+// Open reads the compiled lexicon, the data is used in place and must not change.
+func Open(data []byte) (*Lexicon, error) {
+	if len(data) < headerSize || !bytes.Equal(data[:4], magic[:]) {
+		return nil, ErrFormat
+	}
+	var l = Lexicon{data: data}
+	copy(l.source[:], data[4:])
+	var header = data[4+sha256.Size : headerSize]
+	l.records = int(u32(header, 0))
+	l.strings = int(u32(header, 1))
+	var nTags = int(u32(header, 2))
+	l.pairs = int(u32(header, 3))
+	var textLen, tagTextLen = int(u32(header, 4)), int(u32(header, 5))
+	var nBlocks = (l.strings + blockSize - 1) / blockSize
+
+	var rest = data[headerSize:]
+	var sections = []struct {
+		to   *[]byte
+		size int
+	}{
+		{&l.blocks, 4 * nBlocks},
+		{&l.text, textLen},
+		{&l.tags, tagSize * nTags},
+		{&l.tagText, tagTextLen},
+	}
+	for i := range l.cols {
+		l.cols[i].width = int(header[6*4+i])
+		l.cols[i].n = l.pairs
+		sections = append(sections, struct {
+			to   *[]byte
+			size int
+		}{&l.cols[i].data, l.cols[i].width * l.pairs})
+	}
+	for _, section := range sections {
+		if section.size < 0 || len(rest) < section.size {
+			return nil, ErrFormat
+		}
+		*section.to, rest = rest[:section.size], rest[section.size:]
+	}
+	if len(rest) != 0 {
+		return nil, ErrFormat
+	}
+	return &l, nil
+}
+
+// Read decompresses and opens the compiled lexicon.
+func Read(compressed []byte) (*Lexicon, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data)
+}
+
+// Source returns the SHA-256 checksum of the source lexicon the lexicon was compiled from.
+func (l *Lexicon) Source() [sha256.Size]byte {
+	return l.source
+}
+
+// Records returns the number of records the lexicon was compiled from.
+func (l *Lexicon) Records() int {
+	return l.records
+}
+
+// Len returns the number of pairs.
+func (l *Lexicon) Len() int {
+	return l.pairs
+}
+
+// Size returns the bytes of the lexicon.
+func (l *Lexicon) Size() int {
+	return len(l.data)
+}
+
+// block returns the strings of the block from its start.
+func (l *Lexicon) block(b int) []byte {
+	return l.text[u32(l.blocks, b):]
+}
+
+// next decodes the next string of the block into buf, the first string of a block
+// shares nothing with the previous one.
+func next(text []byte, buf []byte, first bool) ([]byte, []byte) {
+	var shared uint64
+	if !first {
+		var n int
+		shared, n = binary.Uvarint(text)
+		text = text[n:]
+	}
+	length, n := binary.Uvarint(text)
+	text = text[n:]
+	buf = append(buf[:shared], text[:length]...)
+	return text[length:], buf
+}
+
+// head returns the first string of the block in place.
+func (l *Lexicon) head(b int) []byte {
+	text := l.block(b)
+	length, n := binary.Uvarint(text)
+	return text[n : n+int(length)]
+}
+
+// find returns the id of the string.
+func (l *Lexicon) find(s []byte) (int, bool) {
+	var nBlocks = len(l.blocks) / 4
+	// the last block whose first string is not after s
+	b := sort.Search(nBlocks, func(b int) bool {
+		return bytes.Compare(l.head(b), s) > 0
+	}) - 1
+	if b < 0 {
+		return 0, false
+	}
+	var text, buf = l.block(b), make([]byte, 0, 2*len(s))
+	for i := 0; i < blockSize && b*blockSize+i < l.strings; i++ {
+		text, buf = next(text, buf, i == 0)
+		switch bytes.Compare(buf, s) {
+		case 0:
+			return b*blockSize + i, true
+		case 1:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// string returns the string of the id.
+func (l *Lexicon) string(id int) string {
+	if id%blockSize == 0 {
+		return string(l.head(id / blockSize))
+	}
+	var text, buf = l.block(id / blockSize), make([]byte, 0, 64)
+	for i := 0; i <= id%blockSize; i++ {
+		text, buf = next(text, buf, i == 0)
+	}
+	return string(buf)
+}
+
+func (l *Lexicon) entry(i int) (src, dst, tag, order, frequency int) {
+	return l.cols[colSrc].get(i), l.cols[colDst].get(i), l.cols[colTag].get(i), l.cols[colOrder].get(i), l.cols[colFrequency].get(i)
+}
+
+func (l *Lexicon) tag(i int) (key uint32, tags string) {
+	var t = l.tags[tagSize*i:]
+	var offset, length = u32(t, 1), u32(t, 2)
+	return u32(t, 0), string(l.tagText[offset : offset+length])
+}
+
+// at returns the pair i of the index, sorted by the word or, in reverse, by the pronunciation.
+func (l *Lexicon) at(reverse bool, i int) int {
+	if reverse {
+		return l.cols[colReverse].get(i)
+	}
+	return i
+}
+
+func (l *Lexicon) make(reverse bool, i int) (e Entry) {
+	src, dst, tag, order, frequency := l.entry(i)
+	if reverse {
+		src, dst = dst, src
+	}
+	e.Src, e.Dst = l.string(src), l.string(dst)
+	e.Key, e.Tags = l.tag(tag)
+	e.Order, e.Frequency = order, frequency
+	return
+}
+
+// first returns the first position in the index whose key is not before id.
+func (l *Lexicon) first(reverse bool, id int) int {
+	if reverse {
+		return sort.Search(l.pairs, func(i int) bool {
+			return l.cols[colDst].get(l.cols[colReverse].get(i)) >= id
+		})
+	}
+	return sort.Search(l.pairs, func(i int) bool {
+		return l.cols[colSrc].get(i) >= id
+	})
+}
+
+// Lookup returns the pairs of the word or, in reverse, of the pronunciation, with Src
+// set to it.
+func (l *Lexicon) Lookup(reverse bool, word string) (ret []Entry) {
+	id, ok := l.find([]byte(word))
+	if !ok {
+		return nil
+	}
+	var key = &l.cols[colSrc]
+	if reverse {
+		key = &l.cols[colDst]
+	}
+	for i := l.first(reverse, id); i < l.pairs && key.get(l.at(reverse, i)) == id; i++ {
+		ret = append(ret, l.make(reverse, l.at(reverse, i)))
+	}
+	return
+}
+
+// Pair returns the pair of src and dst, which are the pronunciation and the word in reverse.
+func (l *Lexicon) Pair(reverse bool, src, dst string) (Entry, bool) {
+	if reverse {
+		src, dst = dst, src
+	}
+	srcId, ok := l.find([]byte(src))
+	if !ok {
+		return Entry{}, false
+	}
+	dstId, ok := l.find([]byte(dst))
+	if !ok {
+		return Entry{}, false
+	}
+	i := sort.Search(l.pairs, func(i int) bool {
+		s := l.cols[colSrc].get(i)
+		return s > srcId || s == srcId && l.cols[colDst].get(i) >= dstId
+	})
+	if i == l.pairs || l.cols[colSrc].get(i) != srcId || l.cols[colDst].get(i) != dstId {
+		return Entry{}, false
+	}
+	return l.make(reverse, i), true
+}
+
+// TagKey returns the key of the tags, the xor of their hashes.
+func TagKey(tags []string) (key uint32) {
+	for _, tag := range tags {
+		key ^= hash.StringHash(0, tag)
+	}
+	return
+}
+
+type pair struct {
+	tags      map[string]struct{}
+	order     int
+	frequency int
+}
+
+// Builder compiles a lexicon.
+type Builder struct {
+	source  [sha256.Size]byte
+	records int
+	pairs   map[[2]string]*pair
+}
+
+// SetSource records the checksum of the source lexicon, see Lexicon.Source.
+func (b *Builder) SetSource(source [sha256.Size]byte) {
+	b.source = source
+}
+
+// Add adds a record of the word, its pronunciation and its tags. A pair added again
+// gets the union of the tags. Empty tags are dropped, they hash to no key.
+func (b *Builder) Add(src, dst string, tags ...string) {
+	if b.pairs == nil {
+		b.pairs = make(map[[2]string]*pair)
+	}
+	var key = [2]string{src, dst}
+	p := b.pairs[key]
+	if p == nil {
+		p = &pair{tags: make(map[string]struct{}), order: b.records}
+		b.pairs[key] = p
+	}
+	for _, tag := range tags {
+		if tag != "" {
+			p.tags[tag] = struct{}{}
+		}
+	}
+	p.frequency++
+	b.records++
+}
+
+// ReadTSV adds the records of the tab separated lexicon of the word, the pronunciation
+// and the optional json array of tags, with the spaces removed. Each record gets the
+// extra tags too. Records of other lengths are skipped, a file which is not valid tsv
+// adds no records.
+func (b *Builder) ReadTSV(r io.Reader, extra ...string) error {
+	var reader = csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.FieldsPerRecord = -1
+	recs, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+	for _, v := range recs {
+		for i := range v {
+			v[i] = strings.ReplaceAll(v[i], " ", "")
+		}
+		var tags []string
+		switch len(v) {
+		case 2:
+		case 3:
+			if v[2] != "" {
+				// the tags of a malformed cell are dropped
+				_ = json.Unmarshal([]byte(v[2]), &tags)
+			}
+		default:
+			continue
+		}
+		b.Add(v[0], v[1], append(tags, extra...)...)
+	}
+	return nil
+}
+
+// Bytes returns the compiled lexicon.
+func (b *Builder) Bytes() []byte {
+	// the string table
+	var set = make(map[string]int)
+	for key := range b.pairs {
+		set[key[0]], set[key[1]] = 0, 0
+	}
+	var strs = make([]string, 0, len(set))
+	for s := range set {
+		strs = append(strs, s)
+	}
+	sort.Strings(strs)
+	var blocks, text []byte
+	for i, s := range strs {
+		set[s] = i
+		if i%blockSize == 0 {
+			blocks = binary.LittleEndian.AppendUint32(blocks, uint32(len(text)))
+			text = binary.AppendUvarint(text, uint64(len(s)))
+			text = append(text, s...)
+			continue
+		}
+		var prev, shared = strs[i-1], 0
+		for shared < len(prev) && shared < len(s) && prev[shared] == s[shared] {
+			shared++
+		}
+		text = binary.AppendUvarint(text, uint64(shared))
+		text = binary.AppendUvarint(text, uint64(len(s)-shared))
+		text = append(text, s[shared:]...)
+	}
+
+	// the tag table, sorted for a stable output
+	type compiled struct {
+		src, dst  int
+		tags      string
+		order     int
+		frequency int
+	}
+	var entries = make([]compiled, 0, len(b.pairs))
+	var tagKeys = make(map[string]uint32)
+	for key, p := range b.pairs {
+		var tags = make([]string, 0, len(p.tags))
+		for tag := range p.tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		data, _ := json.Marshal(tags)
+		tagKeys[string(data)] = TagKey(tags)
+		entries = append(entries, compiled{src: set[key[0]], dst: set[key[1]], tags: string(data), order: p.order, frequency: p.frequency})
+	}
+	var tagJson = make([]string, 0, len(tagKeys))
+	for tags := range tagKeys {
+		tagJson = append(tagJson, tags)
+	}
+	sort.Strings(tagJson)
+	var tagIds = make(map[string]int)
+	var tagTable, tagText []byte
+	for i, tags := range tagJson {
+		tagIds[tags] = i
+		tagTable = binary.LittleEndian.AppendUint32(tagTable, tagKeys[tags])
+		tagTable = binary.LittleEndian.AppendUint32(tagTable, uint32(len(tagText)))
+		tagTable = binary.LittleEndian.AppendUint32(tagTable, uint32(len(tags)))
+		tagText = append(tagText, tags...)
+	}
+
+	// the pairs, sorted by the word, and the index sorted by the pronunciation
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].src != entries[j].src {
+			return entries[i].src < entries[j].src
+		}
+		return entries[i].dst < entries[j].dst
+	})
+	var values [columns][]int
+	for _, e := range entries {
+		values[colSrc] = append(values[colSrc], e.src)
+		values[colDst] = append(values[colDst], e.dst)
+		values[colTag] = append(values[colTag], tagIds[e.tags])
+		values[colOrder] = append(values[colOrder], e.order)
+		values[colFrequency] = append(values[colFrequency], e.frequency)
+	}
+	for i := range entries {
+		values[colReverse] = append(values[colReverse], i)
+	}
+	sort.Slice(values[colReverse], func(i, j int) bool {
+		a, b := entries[values[colReverse][i]], entries[values[colReverse][j]]
+		if a.dst != b.dst {
+			return a.dst < b.dst
+		}
+		return a.src < b.src
+	})
+
+	var out = append([]byte(nil), magic[:]...)
+	out = append(out, b.source[:]...)
+	for _, v := range []int{b.records, len(strs), len(tagJson), len(entries), len(text), len(tagText)} {
+		out = binary.LittleEndian.AppendUint32(out, uint32(v))
+	}
+	var cols [columns]column
+	for i := range cols {
+		cols[i].width = width(values[i])
+		out = append(out, byte(cols[i].width))
+	}
+	for _, section := range [][]byte{blocks, text, tagTable, tagText} {
+		out = append(out, section...)
+	}
+	for i := range cols {
+		out = cols[i].append(out, values[i])
+	}
+	return out
+}
+
+// Lexicon returns the compiled lexicon.
+func (b *Builder) Lexicon() *Lexicon {
+	l, err := Open(b.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Write compresses the compiled lexicon for storing it as lexicon.zlib.
+func (b *Builder) Write(w io.Writer) error {
+	writer, err := zlib.NewWriterLevel(w, zlib.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(b.Bytes()); err != nil {
+		return err
+	}
+	return writer.Close()
+}
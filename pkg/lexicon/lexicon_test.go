@@ -0,0 +1,191 @@
+package lexicon
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testTSV = "read\tɹˈiːd\t\"[\"\"verb\"\"]\"\n" +
+	"read\tɹˈɛd\t\"[\"\"verb\"\", \"\"past\"\"]\"\n" +
+	"reed\tɹˈiːd\t\n" +
+	"red\tɹˈɛd\t\"[\"\"adj\"\"]\"\n" +
+	"read\tɹˈiːd\t\"[\"\"noun\"\"]\"\n" +
+	"a b\tə\n" +
+	"broken\n"
+
+func testLexicon(t testing.TB) *Lexicon {
+	var b Builder
+	b.SetSource(sha256.Sum256([]byte(testTSV)))
+	if err := b.ReadTSV(strings.NewReader(testTSV), "dict"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLookup(t *testing.T) {
+	l := testLexicon(t)
+	if l.Records() != 6 || l.Len() != 5 || l.Source() != sha256.Sum256([]byte(testTSV)) {
+		t.Fatalf("unexpected lexicon of %d records and %d pairs", l.Records(), l.Len())
+	}
+	got := l.Lookup(false, "read")
+	want := []Entry{
+		{Src: "read", Dst: "ɹˈiːd", Tags: `["dict","noun","verb"]`, Key: TagKey([]string{"dict", "noun", "verb"}), Order: 0, Frequency: 2},
+		{Src: "read", Dst: "ɹˈɛd", Tags: `["dict","past","verb"]`, Key: TagKey([]string{"past", "verb", "dict"}), Order: 1, Frequency: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	got = l.Lookup(true, "ɹˈɛd")
+	if len(got) != 2 || got[0].Src != "ɹˈɛd" || got[0].Dst != "read" || got[1].Dst != "red" || got[1].Order != 3 {
+		t.Fatalf("unexpected reverse lookup %v", got)
+	}
+	if e, ok := l.Pair(true, "ɹˈiːd", "reed"); !ok || e.Tags != `["dict"]` || e.Order != 2 {
+		t.Fatalf("unexpected pair %v %v", e, ok)
+	}
+	if _, ok := l.Pair(false, "reed", "ɹˈɛd"); ok {
+		t.Fatal("found a pair which is not in the lexicon")
+	}
+	if got := l.Lookup(false, "ab"); len(got) != 1 || got[0].Dst != "ə" {
+		t.Fatalf("expected the spaces removed, got %v", got)
+	}
+	for _, word := range []string{"", "a", "rea", "reads", "zzz", "ɹˈiːd"} {
+		if got := l.Lookup(false, word); got != nil {
+			t.Fatalf("%q: expected nothing, got %v", word, got)
+		}
+	}
+	if _, err := Open([]byte("GLX1")); err != ErrFormat {
+		t.Fatalf("expected a format error, got %v", err)
+	}
+}
+
+// testSource reads a real lexicon, the benchmarks skip when the dicts are not around.
+func testSource(b *testing.B) []byte {
+	data, err := os.ReadFile("../../dicts/english/" + SourceName)
+	if err != nil {
+		b.Skip(err)
+	}
+	return data
+}
+
+// legacy builds the maps the lexicon replaced, the way the repository loaded the tsv:
+// the pronunciations of the words with the keys of their tags, the tags of the keys,
+// the keys of the pairs and the order and frequency of the pairs.
+func legacy(data []byte) map[string]map[string]uint32 {
+	reader := csv.NewReader(zlibReader(data))
+	reader.Comma = '\t'
+	recs, _ := reader.ReadAll()
+	words := make(map[string]map[string]uint32)
+	tags := make(map[uint32]string)
+	pairs := make(map[[2]string]uint32)
+	rank := make(map[[2]string][2]int)
+	for i, v := range recs {
+		for j := range v {
+			v[j] = strings.ReplaceAll(v[j], " ", "")
+		}
+		if len(v) < 2 {
+			continue
+		}
+		var set = []string{"dict"}
+		if len(v) == 3 && v[2] != "" {
+			json.Unmarshal([]byte(v[2]), &set)
+			set = append(set, "dict")
+		}
+		if words[v[0]] == nil {
+			words[v[0]] = make(map[string]uint32)
+		} else if key, ok := words[v[0]][v[1]]; ok {
+			var existing []string
+			json.Unmarshal([]byte(tags[key]), &existing)
+			set = append(set, existing...)
+		}
+		sort.Strings(set)
+		tagjson, _ := json.Marshal(set)
+		key := TagKey(set)
+		words[v[0]][v[1]] = key
+		tags[key] = string(tagjson)
+		pairs[[2]string{v[0], v[1]}] = key
+		if r, ok := rank[[2]string{v[0], v[1]}]; ok {
+			r[1]++
+			rank[[2]string{v[0], v[1]}] = r
+		} else {
+			rank[[2]string{v[0], v[1]}] = [2]int{i, 1}
+		}
+	}
+	return words
+}
+
+func zlibReader(data []byte) io.Reader {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return reader
+}
+
+func compile(b *testing.B, data []byte) []byte {
+	var builder Builder
+	if err := builder.ReadTSV(zlibReader(data), "dict"); err != nil {
+		b.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := builder.Write(&buf); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkLoadTSV(b *testing.B) {
+	data := testSource(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy(data)
+	}
+}
+
+func BenchmarkLoadCompiled(b *testing.B) {
+	compiled := compile(b, testSource(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(compiled); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+var words = []string{"hello", "world", "read", "phonemizer", "the", "zebra", "notaword"}
+
+func BenchmarkLookupMap(b *testing.B) {
+	dict := legacy(testSource(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range dict[words[i%len(words)]] {
+		}
+	}
+}
+
+func BenchmarkLookupCompiled(b *testing.B) {
+	l, err := Read(compile(b, testSource(b)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Lookup(false, words[i%len(words)])
+	}
+}
@@ -0,0 +1,229 @@
+// Package manifest describes a model package, the files of one language: the format
+// version, the SHA-256 checksum of every file and, for the model weights among them,
+// the architecture of the network with its hyperparameters, the training date and the
+// checksum of the lexicon the model learned.
+//
+// The manifest is stored in the package as manifest.json.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// FileName is the name of the manifest in a model package.
+const FileName = "manifest.json"
+
+// Version is the format version this package reads and writes.
+const Version = 1
+
+// CrossAttention is the network of crossattention and sochastic layers goruut trains.
+const CrossAttention = "CrossAttention"
+
+var ErrVersion = errors.New("unsupported manifest format version")
+var ErrChecksum = errors.New("checksum mismatch")
+var ErrUnlisted = errors.New("file not listed in the manifest")
+var ErrMissing = errors.New("file listed in the manifest is missing")
+var ErrArchitecture = errors.New("invalid architecture")
+
+// Architecture is the shape of a network.
+type Architecture struct {
+	// Name is the kind of the network, only CrossAttention is known.
+	Name string
+	// Fanout1 is the width of the layers, a third of it is the size of the inference sample.
+	Fanout1 int
+	// Fanout2 multiplies the width of the layers.
+	Fanout2 int
+	// Fanout3 is the number of attention blocks.
+	Fanout3 int
+	// Fanout4 is the number of bits of the first sochastic layer, each next block has 8 bits less.
+	Fanout4 int
+}
+
+// Default is the architecture of the models trained before manifests existed.
+var Default = Architecture{Name: CrossAttention, Fanout1: 24, Fanout2: 1, Fanout3: 4, Fanout4: 32}
+
+// Validate checks that the network of the architecture can be built.
+func (a Architecture) Validate() error {
+	if a.Name != CrossAttention {
+		return fmt.Errorf("%w: unknown network %q", ErrArchitecture, a.Name)
+	}
+	if a.Fanout1 <= 0 || a.Fanout2 <= 0 || a.Fanout3 <= 0 {
+		return fmt.Errorf("%w: fanouts must be positive", ErrArchitecture)
+	}
+	if a.Fanout4 > 255 || a.Fanout4-8*(a.Fanout3-1) <= 0 {
+		return fmt.Errorf("%w: fanout4 %d does not fit %d blocks", ErrArchitecture, a.Fanout4, a.Fanout3)
+	}
+	return nil
+}
+
+// Model describes one file of model weights.
+type Model struct {
+	Architecture
+	// Trained is the training date, such as 2025-01-30.
+	Trained string `json:",omitempty"`
+	// Lexicon is the SHA-256 checksum of the lexicon the model learned.
+	Lexicon string `json:",omitempty"`
+}
+
+// Manifest describes a model package.
+type Manifest struct {
+	Format   int
+	Language string `json:",omitempty"`
+	// Models describes the model weights, keyed by the file name.
+	Models map[string]Model `json:",omitempty"`
+	// Files maps the file names to their SHA-256 checksums in hex.
+	Files map[string]string
+}
+
+// Sum returns the SHA-256 checksum of the data in hex.
+func Sum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Parse reads the manifest and checks its format version and its architectures.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.Format != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, m.Format)
+	}
+	for name, model := range m.Models {
+		if err := model.Validate(); err != nil {
+			return nil, fmt.Errorf("model %s: %w", name, err)
+		}
+	}
+	return &m, nil
+}
+
+// Marshal writes the manifest as indented json.
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Verify checks the data of the file against its checksum.
+func (m *Manifest) Verify(name string, data []byte) error {
+	sum, ok := m.Files[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnlisted, name)
+	}
+	if sum != Sum(data) {
+		return fmt.Errorf("%w: %s", ErrChecksum, name)
+	}
+	return nil
+}
+
+// Architecture returns the architecture of the model file, the default one when the
+// manifest does not describe the file.
+func (m *Manifest) Architecture(name string) Architecture {
+	if m != nil {
+		if model, ok := m.Models[name]; ok {
+			return model.Architecture
+		}
+	}
+	return Default
+}
+
+// Packaged reports whether the file belongs in a model package: the lexicon (missing*
+// and the compiled lexicon*.zlib), the language config (language*.json) and the model
+// weights (weights*.zlib).
+func Packaged(name string) bool {
+	switch {
+	case strings.HasPrefix(name, "missing"):
+		return true
+	case strings.HasPrefix(name, "lexicon") && strings.HasSuffix(name, ".zlib"):
+		return true
+	case strings.HasPrefix(name, "language") && strings.HasSuffix(name, ".json"):
+		return true
+	case strings.HasPrefix(name, "weights") && strings.HasSuffix(name, ".zlib"):
+		return true
+	}
+	return false
+}
+
+// files lists the files of the package, a package is flat.
+func files(fsys fs.FS) (ret []string, err error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && Packaged(entry.Name()) {
+			ret = append(ret, entry.Name())
+		}
+	}
+	return
+}
+
+// VerifyFS checks every file of the package against the manifest: each file needs to
+// be listed and to match its checksum. A partial package, such as a directory
+// overlaying other files, may lack some listed files.
+func (m *Manifest) VerifyFS(fsys fs.FS, partial bool) error {
+	names, err := files(fsys)
+	if err != nil {
+		return err
+	}
+	var seen = make(map[string]bool)
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err := m.Verify(name, data); err != nil {
+			return err
+		}
+		seen[name] = true
+	}
+	if partial {
+		return nil
+	}
+	for _, name := range m.names() {
+		if !seen[name] {
+			return fmt.Errorf("%w: %s", ErrMissing, name)
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) names() (ret []string) {
+	for name := range m.Files {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return
+}
+
+// Create lists every file of the package with its checksum. The models are described
+// by the caller.
+func Create(fsys fs.FS, language string) (*Manifest, error) {
+	names, err := files(fsys)
+	if err != nil {
+		return nil, err
+	}
+	var m = Manifest{
+		Format:   Version,
+		Language: language,
+		Files:    make(map[string]string),
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m.Files[name] = Sum(data)
+	}
+	return &m, nil
+}
@@ -0,0 +1,71 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func testPackage() fstest.MapFS {
+	return fstest.MapFS{
+		"language.json":      {Data: []byte(`{}`)},
+		"missing.tsv":        {Data: []byte("word\tw3d\n")},
+		"weights6.json.zlib": {Data: []byte("weights")},
+		"language.go":        {Data: []byte("package x")},
+	}
+}
+
+func TestCreateVerify(t *testing.T) {
+	fsys := testPackage()
+	m, err := Create(fsys, "Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 3 {
+		t.Fatalf("expected the 3 packaged files, got %v", m.Files)
+	}
+	m.Models = map[string]Model{"weights6.json.zlib": {Architecture: Default, Trained: "2025-01-30"}}
+	data, err := m.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.VerifyFS(fsys, false); err != nil {
+		t.Fatal(err)
+	}
+	if m.Architecture("weights6.json.zlib") != Default || m.Architecture("weights7.json.zlib") != Default {
+		t.Fatal("unexpected architecture")
+	}
+
+	fsys["weights6.json.zlib"] = &fstest.MapFile{Data: []byte("other weights")}
+	if err := m.VerifyFS(fsys, false); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	delete(fsys, "weights6.json.zlib")
+	if err := m.VerifyFS(fsys, false); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected a missing file, got %v", err)
+	}
+	if err := m.VerifyFS(fsys, true); err != nil {
+		t.Fatalf("expected a partial package to verify, got %v", err)
+	}
+	fsys["weights7.json.zlib"] = &fstest.MapFile{Data: []byte("new weights")}
+	if err := m.VerifyFS(fsys, true); !errors.Is(err, ErrUnlisted) {
+		t.Fatalf("expected an unlisted file, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"Format": 2, "Files": {}}`,
+		`{"Format": 1, "Models": {"w": {"Name": "Transformer", "Fanout1": 1, "Fanout2": 1, "Fanout3": 1, "Fanout4": 8}}}`,
+		`{"Format": 1, "Models": {"w": {"Name": "CrossAttention", "Fanout1": 24, "Fanout2": 1, "Fanout3": 5, "Fanout4": 32}}}`,
+		`{"Format": "1"}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("expected an error for %s", data)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package phonealign aligns two sequences by a weighted edit distance and
+// splits IPA strings into phone tokens.
+//
+// The cost matrix is computed by the levenshtein package, the optimal path is
+// then traced back exactly, preferring substitutions over deletions over
+// insertions when several paths have the same cost.
+package phonealign
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/neurlang/levenshtein"
+)
+
+// Op is the edit operation of one aligned pair.
+type Op byte
+
+const (
+	Match Op = iota
+	Substitute
+	Insert
+	Delete
+)
+
+// String returns the lower case name of the operation.
+func (o Op) String() string {
+	switch o {
+	case Match:
+		return "correct"
+	case Substitute:
+		return "substituted"
+	case Insert:
+		return "inserted"
+	case Delete:
+		return "deleted"
+	}
+	return ""
+}
+
+// Pair links the index A of the first sequence to the index B of the second
+// sequence. A is -1 for insertions and B is -1 for deletions.
+type Pair struct {
+	A, B int
+	Op   Op
+	Cost float64
+}
+
+// Costs are the weights of the edit operations, nil functions cost 1.
+// Subst must return 0 for elements considered equal.
+type Costs[A, B any] struct {
+	Subst  func(a A, b B) float64
+	Delete func(a A) float64
+	Insert func(b B) float64
+}
+
+func (c *Costs[A, B]) subst(a A, b B) float64 {
+	if c.Subst == nil {
+		return 1
+	}
+	return c.Subst(a, b)
+}
+
+func (c *Costs[A, B]) del(a A) float64 {
+	if c.Delete == nil {
+		return 1
+	}
+	return c.Delete(a)
+}
+
+func (c *Costs[A, B]) ins(b B) float64 {
+	if c.Insert == nil {
+		return 1
+	}
+	return c.Insert(b)
+}
+
+// Align returns the pairs of the cheapest alignment of a to b and its total cost.
+func Align[A, B any](a []A, b []B, costs Costs[A, B]) (pairs []Pair, distance float64) {
+	var m, n = uint(len(a)), uint(len(b))
+	var width = n + 1
+	var mat = levenshtein.Matrix[float64](m, n,
+		func(i uint) *float64 {
+			c := costs.del(a[i])
+			return &c
+		},
+		func(j uint) *float64 {
+			c := costs.ins(b[j])
+			return &c
+		},
+		func(i, j uint) *float64 {
+			c := costs.subst(a[i], b[j])
+			return &c
+		}, nil)
+
+	distance = *levenshtein.Distance(mat)
+
+	for i, j := m, n; i > 0 || j > 0; {
+		here := mat[width*i+j]
+		if i > 0 && j > 0 {
+			c := costs.subst(a[i-1], b[j-1])
+			if mat[width*(i-1)+(j-1)]+c == here {
+				op := Substitute
+				if c == 0 {
+					op = Match
+				}
+				pairs = append(pairs, Pair{A: int(i - 1), B: int(j - 1), Op: op, Cost: c})
+				i, j = i-1, j-1
+				continue
+			}
+		}
+		if i > 0 {
+			c := costs.del(a[i-1])
+			if mat[width*(i-1)+j]+c == here || j == 0 {
+				pairs = append(pairs, Pair{A: int(i - 1), B: -1, Op: Delete, Cost: c})
+				i--
+				continue
+			}
+		}
+		c := costs.ins(b[j-1])
+		pairs = append(pairs, Pair{A: -1, B: int(j - 1), Op: Insert, Cost: c})
+		j--
+	}
+	for l, r := 0, len(pairs)-1; l < r; l, r = l+1, r-1 {
+		pairs[l], pairs[r] = pairs[r], pairs[l]
+	}
+	return
+}
+
+// IsStress reports whether the rune is an IPA stress mark.
+func IsStress(r rune) bool {
+	return r == 'ˈ' || r == 'ˌ'
+}
+
+// isModifier reports whether the rune modifies the preceding phone, such as
+// combining diacritics, length marks, aspiration and tone letters.
+func isModifier(r rune) bool {
+	if IsStress(r) {
+		return false
+	}
+	return unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) ||
+		unicode.Is(unicode.Lm, r) || unicode.Is(unicode.Sk, r)
+}
+
+// Phones splits an IPA string into phones: a base letter followed by its
+// modifiers. Stress marks are tokens of their own, tie bars join two letters.
+func Phones(ipa string) (ret []string) {
+	for i := 0; i < len(ipa); {
+		r, size := utf8.DecodeRuneInString(ipa[i:])
+		end := i + size
+		if !IsStress(r) {
+			for end < len(ipa) {
+				r, size := utf8.DecodeRuneInString(ipa[end:])
+				if r == '͡' || r == '͜' {
+					end += size
+					if end < len(ipa) {
+						_, size = utf8.DecodeRuneInString(ipa[end:])
+						end += size
+					}
+					continue
+				}
+				if !isModifier(r) {
+					break
+				}
+				end += size
+			}
+		}
+		ret = append(ret, ipa[i:end])
+		i = end
+	}
+	return
+}
+
+// Base strips the modifiers of a phone, leaving its base letters.
+func Base(phone string) string {
+	var out []rune
+	for _, r := range phone {
+		if isModifier(r) || r == '͡' || r == '͜' {
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// PhoneCosts weighs edits of phone tokens as produced by Phones. Phones which
+// differ only in their modifiers (length, diacritics) or stress marks which
+// differ in level cost half a substitution, missing or extra stress marks cost
+// half an insertion or deletion.
+func PhoneCosts() Costs[string, string] {
+	var weight = func(phone string) float64 {
+		if phone != "" && IsStress([]rune(phone)[0]) {
+			return 0.5
+		}
+		return 1
+	}
+	return Costs[string, string]{
+		Subst: func(a, b string) float64 {
+			if a == b {
+				return 0
+			}
+			sa, sb := weight(a) < 1, weight(b) < 1
+			if sa && sb {
+				return 0.5
+			}
+			if sa || sb {
+				// never cheaper than deleting one and inserting the other
+				return 2
+			}
+			if Base(a) == Base(b) {
+				return 0.5
+			}
+			return 1
+		},
+		Delete: weight,
+		Insert: weight,
+	}
+}
@@ -0,0 +1,83 @@
+package phonealign
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPhones(t *testing.T) {
+	got := Phones("ˈt͡ʃaːʰɛ̃˥")
+	want := []string{"ˈ", "t͡ʃ", "aːʰ", "ɛ̃˥"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestAlignOperations(t *testing.T) {
+	a := Phones("kat")
+	b := Phones("kɑts")
+	pairs, distance := Align(a, b, Costs[string, string]{
+		Subst: func(x, y string) float64 {
+			if x == y {
+				return 0
+			}
+			return 1
+		},
+	})
+	if distance != 2 {
+		t.Fatalf("expected distance 2, got %v", distance)
+	}
+	want := []Pair{
+		{A: 0, B: 0, Op: Match},
+		{A: 1, B: 1, Op: Substitute, Cost: 1},
+		{A: 2, B: 2, Op: Match},
+		{A: -1, B: 3, Op: Insert, Cost: 1},
+	}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Fatalf("expected %v, got %v", want, pairs)
+	}
+}
+
+func TestAlignWeighted(t *testing.T) {
+	pairs, distance := Align([]string{"a", "b"}, []string{"b"}, Costs[string, string]{
+		Subst: func(x, y string) float64 {
+			if x == y {
+				return 0
+			}
+			return 5
+		},
+		Delete: func(string) float64 { return 0.5 },
+	})
+	if distance != 0.5 {
+		t.Fatalf("expected distance 0.5, got %v", distance)
+	}
+	if pairs[0].Op != Delete || pairs[1].Op != Match {
+		t.Fatalf("unexpected pairs %v", pairs)
+	}
+}
+
+func TestAlignEmpty(t *testing.T) {
+	pairs, distance := Align(nil, []string{"a"}, Costs[string, string]{})
+	if distance != 1 || len(pairs) != 1 || pairs[0].Op != Insert {
+		t.Fatalf("unexpected alignment %v %v", pairs, distance)
+	}
+}
+
+func TestPhoneCosts(t *testing.T) {
+	costs := PhoneCosts()
+	pairs, distance := Align(Phones("ˈɹiːd"), Phones("rid"), costs)
+	if distance != 2 {
+		t.Fatalf("expected distance 2, got %v", distance)
+	}
+	var ops []string
+	for _, pair := range pairs {
+		ops = append(ops, pair.Op.String())
+	}
+	want := []string{"deleted", "substituted", "substituted", "correct"}
+	if !reflect.DeepEqual(ops, want) {
+		t.Fatalf("expected %v, got %v", want, ops)
+	}
+	if c := costs.Subst("ˈ", "a"); c <= costs.Delete("ˈ")+costs.Insert("a") {
+		t.Fatalf("stress substituted for a phone at cost %v", c)
+	}
+}
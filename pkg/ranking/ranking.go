@@ -0,0 +1,209 @@
+// Package ranking orders pronunciation candidates by an explicit, configurable
+// policy, so that selecting among equally valid candidates is reproducible.
+//
+// A policy is an ordered list of criteria, the first criterion which tells two
+// candidates apart decides. The criteria are:
+//   - hints: candidates whose tags match more of the caller's part of speech
+//     hints come first.
+//   - preferred: candidates preferred by the homograph model come first.
+//   - tags: candidates carrying a tag listed earlier in TagPriority come first,
+//     candidates without any listed tag come last.
+//   - lexicon: candidates listed earlier in the lexicon come first, candidates
+//     which are not in the lexicon come last.
+//   - frequency: candidates with more lexicon records come first.
+//
+// An order which does not list hints ranks by them before its first criterion, so
+// the hints of a request always count. Remaining ties are broken by the phonetic
+// string, so the order never depends on map iteration.
+package ranking
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Criterion names one ranking criterion.
+type Criterion string
+
+const (
+	Hints     Criterion = "hints"
+	Preferred Criterion = "preferred"
+	Tags      Criterion = "tags"
+	Lexicon   Criterion = "lexicon"
+	Frequency Criterion = "frequency"
+)
+
+// Policy is the ranking of candidates, the zero value is the default policy.
+type Policy struct {
+	Order       []Criterion `json:",omitempty"`
+	TagPriority []string    `json:",omitempty"`
+
+	// full is the order with the hints, set by Validate.
+	full []Criterion
+}
+
+// DefaultPolicy ranks hinted first, then preferred, then dictionary entries, then lexicon order, then frequency.
+var DefaultPolicy = Policy{
+	Order:       []Criterion{Hints, Preferred, Tags, Lexicon, Frequency},
+	TagPriority: []string{"dict"},
+}
+
+// Candidate is one pronunciation of a word with the facts the policy ranks by.
+type Candidate struct {
+	Phonetic  string
+	Hints     int
+	Preferred bool
+	Tags      []string
+	// LexiconOrder is the position of the entry in the lexicon, -1 when not in the lexicon.
+	LexiconOrder int
+	Frequency    int
+}
+
+// Validate reports an unknown or repeated criterion. A valid policy keeps its full
+// order, so that the comparisons do not build it again.
+func (p *Policy) Validate() error {
+	var seen = make(map[Criterion]bool)
+	for _, c := range p.Order {
+		switch c {
+		case Hints, Preferred, Tags, Lexicon, Frequency:
+		default:
+			return fmt.Errorf("unknown ranking criterion: %s", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("repeated ranking criterion: %s", c)
+		}
+		seen[c] = true
+	}
+	p.full = p.fullOrder(seen[Hints])
+	return nil
+}
+
+// fullOrder returns the criteria of the policy. An order which leaves out hints ranks
+// by them first, hints sent by a request are never ignored.
+func (p *Policy) fullOrder(hints bool) []Criterion {
+	if len(p.Order) == 0 {
+		return DefaultPolicy.Order
+	}
+	if hints {
+		return p.Order
+	}
+	return append([]Criterion{Hints}, p.Order...)
+}
+
+// order returns the full order of the policy, kept by Validate, or built when the
+// policy was not validated.
+func (p *Policy) order() []Criterion {
+	if p == nil {
+		return DefaultPolicy.Order
+	}
+	if p.full != nil {
+		return p.full
+	}
+	return p.fullOrder(slices.Contains(p.Order, Hints))
+}
+
+func (p *Policy) tagPriority() []string {
+	if p == nil || p.TagPriority == nil {
+		return DefaultPolicy.TagPriority
+	}
+	return p.TagPriority
+}
+
+// tagRank returns the priority of the best listed tag of the candidate.
+func (p *Policy) tagRank(c *Candidate) int {
+	var priority = p.tagPriority()
+	for i, tag := range priority {
+		for _, has := range c.Tags {
+			if has == tag {
+				return i
+			}
+		}
+	}
+	return len(priority)
+}
+
+// lexiconRank maps candidates which are not in the lexicon after all lexicon entries.
+func lexiconRank(c *Candidate) uint {
+	return uint(c.LexiconOrder)
+}
+
+// compare returns a negative number when a ranks before b, positive when after.
+func (p *Policy) compare(a, b *Candidate) int {
+	for _, criterion := range p.order() {
+		switch criterion {
+		case Hints:
+			if d := b.Hints - a.Hints; d != 0 {
+				return d
+			}
+		case Preferred:
+			if a.Preferred != b.Preferred {
+				if a.Preferred {
+					return -1
+				}
+				return 1
+			}
+		case Tags:
+			if d := p.tagRank(a) - p.tagRank(b); d != 0 {
+				return d
+			}
+		case Lexicon:
+			if ra, rb := lexiconRank(a), lexiconRank(b); ra != rb {
+				if ra < rb {
+					return -1
+				}
+				return 1
+			}
+		case Frequency:
+			if d := b.Frequency - a.Frequency; d != 0 {
+				return d
+			}
+		}
+	}
+	switch {
+	case a.Phonetic < b.Phonetic:
+		return -1
+	case a.Phonetic > b.Phonetic:
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether a ranks before b.
+func (p *Policy) Less(a, b *Candidate) bool {
+	return p.compare(a, b) < 0
+}
+
+// Sort orders the candidates from the best to the worst.
+func (p *Policy) Sort(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return p.Less(&candidates[i], &candidates[j])
+	})
+}
+
+// MatchHints counts the hints satisfied by the tags. Both are compared case
+// insensitively, a hint also matches a part of a tag split at underscores, and
+// the value of a feature hint such as "Tense=Past" is matched the same way, so
+// the hints "VERB" and "Tense=Past" both match the tag "past_tense_verb".
+func MatchHints(hints, tags []string) (n int) {
+	var parts = make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		parts[tag] = true
+		for _, part := range strings.Split(tag, "_") {
+			parts[part] = true
+		}
+	}
+	for _, hint := range hints {
+		hint = strings.ToLower(hint)
+		if parts[hint] {
+			n++
+			continue
+		}
+		if _, value, ok := strings.Cut(hint, "="); ok && parts[value] {
+			n++
+		}
+	}
+	return
+}
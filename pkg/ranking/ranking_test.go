@@ -0,0 +1,128 @@
+package ranking
+
+import (
+	"reflect"
+	"testing"
+)
+
+func phonetics(candidates []Candidate) (ret []string) {
+	for _, c := range candidates {
+		ret = append(ret, c.Phonetic)
+	}
+	return
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	candidates := []Candidate{
+		{Phonetic: "model", LexiconOrder: -1},
+		{Phonetic: "late", Tags: []string{"dict"}, LexiconOrder: 5},
+		{Phonetic: "early", Tags: []string{"dict"}, LexiconOrder: 1},
+		{Phonetic: "chosen", Tags: []string{"dict"}, LexiconOrder: 9, Preferred: true},
+	}
+	var policy Policy
+	policy.Sort(candidates)
+	want := []string{"chosen", "early", "late", "model"}
+	if got := phonetics(candidates); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestConfiguredPolicy(t *testing.T) {
+	candidates := []Candidate{
+		{Phonetic: "b", Tags: []string{"dict"}, LexiconOrder: 0, Frequency: 1},
+		{Phonetic: "a", Tags: []string{"dict", "noun"}, LexiconOrder: 2, Frequency: 1},
+		{Phonetic: "c", Tags: []string{"dict"}, LexiconOrder: 1, Frequency: 3},
+	}
+	policy := Policy{
+		Order:       []Criterion{Tags, Frequency, Lexicon},
+		TagPriority: []string{"noun", "dict"},
+	}
+	policy.Sort(candidates)
+	want := []string{"a", "c", "b"}
+	if got := phonetics(candidates); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTiesAreStable(t *testing.T) {
+	policy := Policy{Order: []Criterion{Preferred}}
+	for i := 0; i < 10; i++ {
+		candidates := []Candidate{{Phonetic: "y"}, {Phonetic: "x"}, {Phonetic: "z"}}
+		policy.Sort(candidates)
+		if got := phonetics(candidates); !reflect.DeepEqual(got, []string{"x", "y", "z"}) {
+			t.Fatalf("unexpected order %v", got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, policy := range []Policy{
+		{Order: []Criterion{"popularity"}},
+		{Order: []Criterion{Tags, Tags}},
+	} {
+		if policy.Validate() == nil {
+			t.Fatalf("expected policy %v to be invalid", policy.Order)
+		}
+	}
+	if err := DefaultPolicy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchHints(t *testing.T) {
+	past := []string{"dict", "past_tense_verb"}
+	present := []string{"dict", "present_tense_verb"}
+	hints := []string{"VERB", "Tense=Past"}
+	if n := MatchHints(hints, past); n != 2 {
+		t.Fatalf("expected 2 hints to match the past tense, got %d", n)
+	}
+	if n := MatchHints(hints, present); n != 1 {
+		t.Fatalf("expected 1 hint to match the present tense, got %d", n)
+	}
+	if n := MatchHints([]string{"noun"}, present); n != 0 {
+		t.Fatalf("expected no hint to match, got %d", n)
+	}
+}
+
+func TestHintsRankFirst(t *testing.T) {
+	candidates := []Candidate{
+		{Phonetic: "a", Preferred: true, LexiconOrder: 0},
+		{Phonetic: "b", Hints: 1, LexiconOrder: 1},
+	}
+	DefaultPolicy.Sort(candidates)
+	if candidates[0].Phonetic != "b" {
+		t.Fatalf("expected the hinted candidate first, got %v", phonetics(candidates))
+	}
+}
+
+func TestHintsLeftOutRankFirst(t *testing.T) {
+	candidates := []Candidate{
+		{Phonetic: "a", Tags: []string{"dict"}, LexiconOrder: 0},
+		{Phonetic: "b", Hints: 1, LexiconOrder: 1},
+	}
+	policy := Policy{Order: []Criterion{Tags, Lexicon}}
+	policy.Sort(candidates)
+	if candidates[0].Phonetic != "b" {
+		t.Fatalf("expected the hinted candidate first, got %v", phonetics(candidates))
+	}
+	candidates[0], candidates[1] = candidates[1], candidates[0]
+	policy = Policy{Order: []Criterion{Tags, Hints}}
+	policy.Sort(candidates)
+	if candidates[0].Phonetic != "a" {
+		t.Fatalf("expected hints after the tags, got %v", phonetics(candidates))
+	}
+}
+
+func TestValidateKeepsOrder(t *testing.T) {
+	policy := Policy{Order: []Criterion{Tags, Lexicon}}
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []Criterion{Hints, Tags, Lexicon}; !reflect.DeepEqual(policy.order(), want) {
+		t.Fatalf("expected %v, got %v", want, policy.order())
+	}
+	a, b := &Candidate{Phonetic: "a"}, &Candidate{Phonetic: "b"}
+	if n := testing.AllocsPerRun(100, func() { policy.Less(a, b) }); n != 0 {
+		t.Fatalf("expected no allocations per comparison, got %v", n)
+	}
+}
@@ -0,0 +1,261 @@
+// Package registry lists the languages goruut has data for and resolves language
+// tags to them. Each language package under dicts registers itself from init().
+//
+// A language is found by its goruut name (EnglishAmerican), one of its aliases,
+// its directory (english/american) or a BCP-47 tag (en-US). Tags are matched by
+// their language subtag, an ISO 639-1 or ISO 639-3 code, then narrowed by the
+// script, the region and the variants, where private use subtags count as
+// variants (vi-VN-x-south). When nothing matches, the variants, then the region,
+// then the script are dropped in turn. Among languages matching equally, the
+// default of the code wins, then the one with the fewest region and variant
+// restrictions, then the first name.
+package registry
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Language describes the data of one language.
+type Language struct {
+	// Name is the goruut name of the language, such as EnglishAmerican.
+	Name string
+	// Dir is the directory of the language data under dicts, such as english/american.
+	Dir string
+	// Display is the human readable name, such as English (American).
+	Display string
+	// ISO6391 is the two letter ISO 639-1 code, empty when the language has none.
+	ISO6391 string `json:",omitempty"`
+	// ISO6393 is the three letter ISO 639-3 code, empty when the language has none.
+	ISO6393 string `json:",omitempty"`
+	// Script is the ISO 15924 code of the script, such as Latn.
+	Script string `json:",omitempty"`
+	// Region is the ISO 3166-1 code of the region of the variety, such as US.
+	Region string `json:",omitempty"`
+	// Variant names the dialect or the variety, such as south.
+	Variant string `json:",omitempty"`
+	// Default marks the language picked for its code when the tag does not narrow it down.
+	Default bool `json:",omitempty"`
+	// Aliases are other names of the language, such as the names goruut used before.
+	Aliases []string `json:",omitempty"`
+
+	// FS holds the language files.
+	FS fs.ReadFileFS `json:"-"`
+}
+
+// Registry is a set of languages.
+type Registry struct {
+	mut    sync.RWMutex
+	byName map[string]*Language
+	byCode map[string][]*Language
+	all    []*Language
+}
+
+// Register adds the language, a name, alias or directory already taken is overwritten.
+func (r *Registry) Register(l Language) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.byName == nil {
+		r.byName = make(map[string]*Language)
+		r.byCode = make(map[string][]*Language)
+	}
+	var lang = &l
+	r.all = append(r.all, lang)
+	for _, name := range append([]string{l.Name, l.Dir}, l.Aliases...) {
+		if name != "" {
+			r.byName[strings.ToLower(name)] = lang
+		}
+	}
+	for _, code := range []string{l.ISO6391, l.ISO6393} {
+		if code != "" {
+			code = strings.ToLower(code)
+			r.byCode[code] = append(r.byCode[code], lang)
+		}
+	}
+}
+
+// Lookup finds the language by its name, alias or directory, ignoring case. It returns nil when none matches.
+func (r *Registry) Lookup(name string) *Language {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return r.byName[strings.ToLower(name)]
+}
+
+// Resolve finds the language by its name, alias or directory, else by the BCP-47 tag.
+// It returns nil when none matches.
+func (r *Registry) Resolve(tag string) *Language {
+	if l := r.Lookup(tag); l != nil {
+		return l
+	}
+	t, ok := parseTag(tag)
+	if !ok {
+		return nil
+	}
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	candidates := r.byCode[t.language]
+	for _, narrowed := range t.fallback() {
+		var best *Language
+		for _, l := range candidates {
+			if narrowed.matches(l) && (best == nil || better(l, best)) {
+				best = l
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return nil
+}
+
+// All lists the languages sorted by name.
+func (r *Registry) All() (ret []Language) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	for _, l := range r.all {
+		ret = append(ret, *l)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return
+}
+
+func restrictions(l *Language) (n int) {
+	if l.Region != "" {
+		n++
+	}
+	if l.Variant != "" {
+		n++
+	}
+	return
+}
+
+func better(a, b *Language) bool {
+	if a.Default != b.Default {
+		return a.Default
+	}
+	if restrictions(a) != restrictions(b) {
+		return restrictions(a) < restrictions(b)
+	}
+	return a.Name < b.Name
+}
+
+// tag is a parsed BCP-47 tag, lower case.
+type tag struct {
+	language string
+	script   string
+	region   string
+	variants []string
+}
+
+// parseTag splits a BCP-47 tag into its subtags, "_" separates subtags too. Private
+// use subtags are taken as variants, other extensions are skipped.
+func parseTag(s string) (t tag, ok bool) {
+	subtags := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(subtags) == 0 || len(subtags[0]) < 2 || len(subtags[0]) > 3 || !alpha(subtags[0]) {
+		return t, false
+	}
+	t.language = subtags[0]
+	var extension, private bool
+	for _, sub := range subtags[1:] {
+		switch {
+		case private:
+			t.variants = append(t.variants, sub)
+		case sub == "x":
+			private = true
+		case len(sub) == 1:
+			extension = true
+		case extension:
+		case t.script == "" && t.region == "" && len(t.variants) == 0 && len(sub) == 4 && alpha(sub):
+			t.script = sub
+		case t.region == "" && len(t.variants) == 0 && (len(sub) == 2 && alpha(sub) || len(sub) == 3 && digits(sub)):
+			t.region = sub
+		default:
+			t.variants = append(t.variants, sub)
+		}
+	}
+	return t, true
+}
+
+func alpha(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// fallback lists the tag narrowed less and less: without the variants, without the region, without the script.
+func (t tag) fallback() []tag {
+	var ret = []tag{t}
+	for _, drop := range []func(*tag){
+		func(t *tag) { t.variants = nil },
+		func(t *tag) { t.region = "" },
+		func(t *tag) { t.script = "" },
+	} {
+		drop(&t)
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// matches reports whether the language fits every subtag of the tag, variants match by prefix, so south matches southern.
+func (t tag) matches(l *Language) bool {
+	if t.script != "" && !strings.EqualFold(t.script, l.Script) {
+		return false
+	}
+	if t.region != "" && !strings.EqualFold(t.region, l.Region) {
+		return false
+	}
+	for _, variant := range t.variants {
+		if l.Variant == "" || !strings.HasPrefix(variant, strings.ToLower(l.Variant)) && !strings.HasPrefix(strings.ToLower(l.Variant), variant) {
+			return false
+		}
+	}
+	return true
+}
+
+var languages Registry
+
+// Register adds the language to the registry of goruut.
+func Register(l Language) {
+	languages.Register(l)
+}
+
+// Lookup finds the language of goruut by its name, alias or directory.
+func Lookup(name string) *Language {
+	return languages.Lookup(name)
+}
+
+// Resolve finds the language of goruut by its name, alias, directory or BCP-47 tag.
+func Resolve(tag string) *Language {
+	return languages.Resolve(tag)
+}
+
+// Canonical returns the goruut name of the language, or the string itself when no language matches.
+func Canonical(tag string) string {
+	if l := languages.Resolve(tag); l != nil {
+		return l.Name
+	}
+	return tag
+}
+
+// All lists the languages of goruut sorted by name.
+func All() []Language {
+	return languages.All()
+}
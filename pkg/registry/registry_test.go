@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"testing"
+)
+
+func testRegistry() *Registry {
+	var r Registry
+	for _, l := range []Language{
+		{Name: "English", Dir: "english", ISO6391: "en", ISO6393: "eng", Script: "Latn", Default: true},
+		{Name: "EnglishAmerican", Dir: "english/american", ISO6391: "en", ISO6393: "eng", Script: "Latn", Region: "US"},
+		{Name: "EnglishBritish", Dir: "english/british", ISO6391: "en", ISO6393: "eng", Script: "Latn", Region: "GB"},
+		{Name: "Hebrew2", Dir: "hebrew2", ISO6391: "he", ISO6393: "heb", Script: "Hebr", Variant: "vocalized"},
+		{Name: "Hebrew3", Dir: "hebrew3", ISO6391: "he", ISO6393: "heb", Script: "Hebr", Default: true, Aliases: []string{"Hebrew"}},
+		{Name: "VietnameseNorthern", Dir: "vietnamese/northern", ISO6391: "vi", ISO6393: "vie", Region: "VN", Variant: "north", Default: true},
+		{Name: "VietnameseSouthern", Dir: "vietnamese/southern", ISO6391: "vi", ISO6393: "vie", Region: "VN", Variant: "south"},
+		{Name: "ChineseMandarin", Dir: "chinese/mandarin", ISO6391: "zh", ISO6393: "cmn", Script: "Hans", Default: true},
+		{Name: "Cantonese", Dir: "cantonese", ISO6393: "yue", Script: "Hant", Aliases: []string{"zh-HK"}},
+		{Name: "MalayArab", Dir: "malay/arab", ISO6391: "ms", ISO6393: "msa", Script: "Arab"},
+		{Name: "MalayLatin", Dir: "malay/latin", ISO6391: "ms", ISO6393: "msa", Script: "Latn", Default: true},
+	} {
+		r.Register(l)
+	}
+	return &r
+}
+
+func TestResolve(t *testing.T) {
+	r := testRegistry()
+	for tag, want := range map[string]string{
+		"EnglishAmerican":     "EnglishAmerican",
+		"englishamerican":     "EnglishAmerican",
+		"english/british":     "EnglishBritish",
+		"Hebrew":              "Hebrew3",
+		"en":                  "English",
+		"eng":                 "English",
+		"en-US":               "EnglishAmerican",
+		"en_gb":               "EnglishBritish",
+		"en-AU":               "English",
+		"en-Latn-US":          "EnglishAmerican",
+		"he":                  "Hebrew3",
+		"he-x-vocalized":      "Hebrew2",
+		"vi":                  "VietnameseNorthern",
+		"vi-VN-x-south":       "VietnameseSouthern",
+		"vi-VN-x-southern":    "VietnameseSouthern",
+		"vi-x-central":        "VietnameseNorthern",
+		"zh-Hans":             "ChineseMandarin",
+		"zh-HK":               "Cantonese",
+		"yue-Hant":            "Cantonese",
+		"ms-Arab":             "MalayArab",
+		"ms-MY":               "MalayLatin",
+		"en-US-u-ca-gregory":  "EnglishAmerican",
+		"zh-Hans-CN-x-pinyin": "ChineseMandarin",
+	} {
+		l := r.Resolve(tag)
+		if l == nil {
+			t.Errorf("%s: expected %s, got nothing", tag, want)
+		} else if l.Name != want {
+			t.Errorf("%s: expected %s, got %s", tag, want, l.Name)
+		}
+	}
+	for _, tag := range []string{"", "fr", "x-south", "e", "english-US", "12"} {
+		if l := r.Resolve(tag); l != nil {
+			t.Errorf("%s: expected nothing, got %s", tag, l.Name)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	all := testRegistry().All()
+	if len(all) != 11 || all[0].Name != "Cantonese" || all[10].Name != "VietnameseSouthern" {
+		t.Fatalf("unexpected languages: %v", all)
+	}
+}
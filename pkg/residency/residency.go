@@ -0,0 +1,178 @@
+// Package residency bounds the memory the loaded languages take. The repositories
+// report the approximate bytes they hold for a language, each request marks its
+// languages used, and while the languages together exceed the budget the least
+// recently used ones are unloaded from every repository at once. An unloaded
+// language is loaded again by the next request for it. Pinned languages are never
+// unloaded.
+package residency
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neurlang/goruut/helpers/log"
+)
+
+// Language is the residency of one language.
+type Language struct {
+	Name string
+	// Bytes is the approximate memory the repositories hold for the language, zero when unloaded.
+	Bytes    int64
+	Pinned   bool `json:",omitempty"`
+	LastUsed time.Time
+	// Loads counts the times the language was loaded, Evictions the times it was unloaded for the budget.
+	Loads     uint64
+	Evictions uint64
+}
+
+// Report is the state of the residency.
+type Report struct {
+	// Budget is the bound of the resident bytes, zero when unbounded.
+	Budget int64
+	// Resident sums up the bytes of the languages.
+	Resident  int64
+	Loads     uint64
+	Evictions uint64
+	// Languages are sorted from the most recently used.
+	Languages []Language
+}
+
+// Manager tracks the languages in memory.
+type Manager struct {
+	budget int64
+	now    func() time.Time
+
+	mut       sync.Mutex
+	langs     map[string]*Language
+	resident  int64
+	loads     uint64
+	evictions uint64
+	listeners []func(lang string)
+	// warned is set once the languages which cannot be unloaded exceeded the budget, until the next load.
+	warned bool
+}
+
+// New creates a manager keeping the languages within the budget in bytes, zero is unbounded.
+func New(budget int64, pinned ...string) *Manager {
+	m := &Manager{
+		budget: budget,
+		now:    time.Now,
+		langs:  make(map[string]*Language),
+	}
+	for _, name := range pinned {
+		m.lang(name).Pinned = true
+	}
+	return m
+}
+
+func (m *Manager) lang(name string) *Language {
+	l := m.langs[name]
+	if l == nil {
+		l = &Language{Name: name}
+		m.langs[name] = l
+	}
+	return l
+}
+
+// OnEvict adds a listener called with the language to unload.
+func (m *Manager) OnEvict(listener func(lang string)) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// Loaded adds the bytes a repository holds for the language.
+func (m *Manager) Loaded(lang string, bytes int64) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	l := m.lang(lang)
+	if l.Bytes == 0 && bytes > 0 {
+		l.Loads++
+		m.loads++
+		log.Now().Infof("Residency loading language %s", lang)
+	}
+	l.Bytes += bytes
+	m.resident += bytes
+	m.warned = false
+}
+
+// Forget drops the bytes of the language without telling the listeners, for a language
+// the repositories forgot themselves, such as one whose files changed.
+func (m *Manager) Forget(lang string) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	if l := m.langs[lang]; l != nil {
+		m.resident -= l.Bytes
+		l.Bytes = 0
+	}
+}
+
+// Use marks the language used and unloads the least recently used languages, except the
+// pinned ones and the language itself, while the resident bytes exceed the budget.
+func (m *Manager) Use(lang string) {
+	m.mut.Lock()
+	m.lang(lang).LastUsed = m.now()
+	victims := m.victims(lang)
+	listeners := m.listeners
+	m.mut.Unlock()
+
+	for _, victim := range victims {
+		for _, listener := range listeners {
+			listener(victim)
+		}
+	}
+}
+
+// victims picks the languages to unload and drops their bytes.
+func (m *Manager) victims(used string) (ret []string) {
+	if m.budget <= 0 || m.resident <= m.budget {
+		return nil
+	}
+	var candidates []*Language
+	for _, l := range m.langs {
+		if l.Bytes > 0 && !l.Pinned && l.Name != used {
+			candidates = append(candidates, l)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].LastUsed.Equal(candidates[j].LastUsed) {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return candidates[i].LastUsed.Before(candidates[j].LastUsed)
+	})
+	for _, l := range candidates {
+		if m.resident <= m.budget {
+			break
+		}
+		log.Now().Infof("Residency unloading language %s, %d bytes of %d over the budget %d", l.Name, l.Bytes, m.resident, m.budget)
+		m.resident -= l.Bytes
+		l.Bytes = 0
+		l.Evictions++
+		m.evictions++
+		ret = append(ret, l.Name)
+	}
+	if m.resident > m.budget && !m.warned {
+		m.warned = true
+		log.Now().Warningf("Residency over the budget %d with %d bytes of pinned and used languages", m.budget, m.resident)
+	}
+	return
+}
+
+// Report returns the state of the residency.
+func (m *Manager) Report() (r Report) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	r.Budget, r.Resident, r.Loads, r.Evictions = m.budget, m.resident, m.loads, m.evictions
+	r.Languages = []Language{}
+	for _, l := range m.langs {
+		r.Languages = append(r.Languages, *l)
+	}
+	sort.Slice(r.Languages, func(i, j int) bool {
+		if r.Languages[i].LastUsed.Equal(r.Languages[j].LastUsed) {
+			return r.Languages[i].Name < r.Languages[j].Name
+		}
+		return r.Languages[i].LastUsed.After(r.Languages[j].LastUsed)
+	})
+	return
+}
@@ -0,0 +1,73 @@
+package residency
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testManager(budget int64, pinned ...string) (*Manager, *[]string) {
+	m := New(budget, pinned...)
+	var clock time.Time
+	m.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	var evicted []string
+	m.OnEvict(func(lang string) {
+		evicted = append(evicted, lang)
+	})
+	return m, &evicted
+}
+
+func TestEvictLeastRecentlyUsed(t *testing.T) {
+	m, evicted := testManager(130, "Pinned")
+	for _, lang := range []string{"Pinned", "A", "B", "C"} {
+		m.Use(lang)
+		m.Loaded(lang, 30)
+		m.Loaded(lang, 10)
+	}
+	if len(*evicted) != 0 {
+		t.Fatalf("unexpected evictions %v", *evicted)
+	}
+	m.Use("A")
+	if !reflect.DeepEqual(*evicted, []string{"B"}) {
+		t.Fatalf("expected B evicted, got %v", *evicted)
+	}
+	r := m.Report()
+	if r.Resident != 120 || r.Evictions != 1 || r.Loads != 4 {
+		t.Fatalf("unexpected report %+v", r)
+	}
+	if r.Languages[0].Name != "A" || r.Languages[3].Name != "Pinned" || !r.Languages[3].Pinned {
+		t.Fatalf("unexpected order %+v", r.Languages)
+	}
+
+	m.Loaded("B", 40)
+	m.Use("B")
+	if !reflect.DeepEqual(*evicted, []string{"B", "C"}) {
+		t.Fatalf("expected C evicted, got %v", *evicted)
+	}
+}
+
+func TestPinnedOverBudget(t *testing.T) {
+	m, evicted := testManager(10, "Pinned")
+	m.Loaded("Pinned", 20)
+	m.Loaded("A", 20)
+	m.Use("A")
+	if len(*evicted) != 0 {
+		t.Fatalf("evicted a pinned or used language: %v", *evicted)
+	}
+	m.Forget("A")
+	if r := m.Report(); r.Resident != 20 || r.Evictions != 0 {
+		t.Fatalf("unexpected report %+v", r)
+	}
+}
+
+func TestUnbounded(t *testing.T) {
+	m, evicted := testManager(0)
+	m.Loaded("A", 1<<40)
+	m.Use("B")
+	if len(*evicted) != 0 {
+		t.Fatalf("unexpected evictions %v", *evicted)
+	}
+}
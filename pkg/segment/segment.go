@@ -0,0 +1,95 @@
+// Package segment splits a word into the units the model reads, such as the Czech
+// "ch" or the English "th". The multi letter units of a language are kept in a rune
+// trie, so each position of the word is matched once against the longest unit
+// starting there. Suffix units, such as combining marks, are appended to the unit
+// before them.
+package segment
+
+// node is a node of the rune trie, end marks the last rune of a unit.
+type node struct {
+	next map[rune]*node
+	end  bool
+}
+
+func (n *node) add(unit string) {
+	for _, r := range unit {
+		if n.next == nil {
+			n.next = make(map[rune]*node)
+		}
+		child := n.next[r]
+		if child == nil {
+			child = &node{}
+			n.next[r] = child
+		}
+		n = child
+	}
+	n.end = true
+}
+
+// match returns the rune length of the longest unit the word starts with, zero for none.
+func (n *node) match(word []rune) (length int) {
+	for i, r := range word {
+		if n = n.next[r]; n == nil {
+			break
+		}
+		if n.end {
+			length = i + 1
+		}
+	}
+	return
+}
+
+// Segmenter splits the words of one language. A nil segmenter splits words into runes.
+type Segmenter struct {
+	multi  node
+	suffix node
+}
+
+// New creates the segmenter of the multi letter units and the suffix units.
+func New(multi, suffix map[string]struct{}) *Segmenter {
+	s := &Segmenter{}
+	for unit := range multi {
+		s.multi.add(unit)
+	}
+	for unit := range suffix {
+		s.suffix.add(unit)
+	}
+	return s
+}
+
+// Multi returns the multi letter units of a language: the listed ones and the keys of
+// its Map which are longer than a rune and have some options.
+func Multi(mapping map[string][]string, multi []string) map[string]struct{} {
+	out := make(map[string]struct{})
+	for _, unit := range multi {
+		out[unit] = struct{}{}
+	}
+	for unit, options := range mapping {
+		if len(options) > 0 && len([]rune(unit)) > 1 {
+			out[unit] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Slice splits the word. At each position the longest multi letter unit is taken, else
+// the longest suffix unit is appended to the previous unit, else the rune is taken alone.
+func (s *Segmenter) Slice(word []rune) (o []string) {
+	for i := 0; i < len(word); {
+		if s != nil {
+			if n := s.multi.match(word[i:]); n > 0 {
+				o = append(o, string(word[i:i+n]))
+				i += n
+				continue
+			}
+			if n := s.suffix.match(word[i:]); n > 0 && len(o) > 0 {
+				o[len(o)-1] += string(word[i : i+n])
+				i += n
+				continue
+			}
+		}
+		o = append(o, string(word[i]))
+		i++
+	}
+	return o
+}
@@ -0,0 +1,45 @@
+package segment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func set(units ...string) map[string]struct{} {
+	out := make(map[string]struct{})
+	for _, unit := range units {
+		out[unit] = struct{}{}
+	}
+	return out
+}
+
+func TestSlice(t *testing.T) {
+	s := New(set("ch", "sch", "dž", "ou", "tsch"), set("\u0301", "ː"))
+	for word, want := range map[string][]string{
+		"chata":   {"ch", "a", "t", "a"},
+		"deutsch": {"d", "e", "u", "tsch"},
+		"schon":   {"sch", "o", "n"},
+		"džus":    {"dž", "u", "s"},
+		"dzus":    {"d", "z", "u", "s"},
+		"ouː":     {"ouː"},
+		"ːa":      {"ː", "a"},
+		"a\u0301": {"a\u0301"},
+		"c":       {"c"},
+		"":        nil,
+	} {
+		if got := s.Slice([]rune(word)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected %q, got %q", word, want, got)
+		}
+	}
+	var none *Segmenter
+	if got := none.Slice([]rune("ch")); !reflect.DeepEqual(got, []string{"c", "h"}) {
+		t.Errorf("expected runes, got %q", got)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	got := Multi(map[string][]string{"ch": {"x"}, "c": {"ts"}, "qu": nil, "ll": {"j"}}, []string{"sch"})
+	if want := set("ch", "ll", "sch"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
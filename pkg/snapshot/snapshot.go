@@ -0,0 +1,97 @@
+// Package snapshot holds values keyed by name, such as the loaded languages of a
+// repository, for lock-free reading. Each value is immutable once published, the map
+// of them is published through an atomic pointer and replaced by a copy whenever a
+// value is added or forgotten. Each value is loaded once, outside of any lock, so that
+// loading one key does not hold up the readers of the others.
+package snapshot
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Map holds the values by key. The zero Map is not usable, create it by New.
+type Map[T any] struct {
+	current atomic.Pointer[map[string]*T]
+
+	// mut serializes the writers of the map and guards the loads in flight.
+	mut     sync.Mutex
+	loading map[string]*flight[T]
+}
+
+// flight is a load in progress, the callers of the same key wait for it to be done.
+type flight[T any] struct {
+	done  chan struct{}
+	value *T
+}
+
+// New creates an empty map.
+func New[T any]() *Map[T] {
+	s := &Map[T]{loading: make(map[string]*flight[T])}
+	s.current.Store(&map[string]*T{})
+	return s
+}
+
+// Get returns the loaded value of the key, nil when it is not loaded.
+func (s *Map[T]) Get(key string) *T {
+	return (*s.current.Load())[key]
+}
+
+// Load returns the value of the key, calling load when it is not loaded yet. Loaded is
+// true for the call which loaded and published the value. A nil value is not kept, so
+// the next call tries loading again.
+func (s *Map[T]) Load(key string, load func() *T) (value *T, loaded bool) {
+	if value := s.Get(key); value != nil {
+		return value, false
+	}
+	s.mut.Lock()
+	if value := s.Get(key); value != nil {
+		s.mut.Unlock()
+		return value, false
+	}
+	if f := s.loading[key]; f != nil {
+		s.mut.Unlock()
+		<-f.done
+		return f.value, false
+	}
+	f := &flight[T]{done: make(chan struct{})}
+	s.loading[key] = f
+	s.mut.Unlock()
+
+	defer func() {
+		s.mut.Lock()
+		// a key forgotten meanwhile may have loaded stale files, it is not published
+		if s.loading[key] == f {
+			delete(s.loading, key)
+			if f.value != nil {
+				s.publish(func(m map[string]*T) { m[key] = f.value })
+				loaded = true
+			}
+		}
+		s.mut.Unlock()
+		close(f.done)
+	}()
+	f.value = load()
+	return f.value, false
+}
+
+// Forget drops the value of the key and abandons its load in flight.
+func (s *Map[T]) Forget(key string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	delete(s.loading, key)
+	if s.Get(key) != nil {
+		s.publish(func(m map[string]*T) { delete(m, key) })
+	}
+}
+
+// publish replaces the map by a changed copy, the caller holds mut.
+func (s *Map[T]) publish(change func(map[string]*T)) {
+	old := *s.current.Load()
+	m := make(map[string]*T, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	change(m)
+	s.current.Store(&m)
+}
@@ -0,0 +1,156 @@
+package snapshot
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadOnce(t *testing.T) {
+	m := New[int]()
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	var loads atomic.Int32
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, loaded := m.Load("a", func() *int {
+				calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				v := 1
+				return &v
+			})
+			if v == nil || *v != 1 {
+				t.Errorf("unexpected value %v", v)
+			}
+			if loaded {
+				loads.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 || loads.Load() != 1 {
+		t.Fatalf("expected one load, got %d calls and %d loads", calls.Load(), loads.Load())
+	}
+	if m.Get("a") == nil || m.Get("b") != nil {
+		t.Fatal("unexpected values")
+	}
+}
+
+func TestNilNotKept(t *testing.T) {
+	m := New[int]()
+	for i := 0; i < 2; i++ {
+		if v, loaded := m.Load("a", func() *int { return nil }); v != nil || loaded {
+			t.Fatalf("unexpected value %v %v", v, loaded)
+		}
+	}
+	var calls int
+	m.Load("a", func() *int { calls++; return new(int) })
+	if calls != 1 || m.Get("a") == nil {
+		t.Fatal("expected the value loaded after a nil one")
+	}
+}
+
+func TestForgetInFlight(t *testing.T) {
+	m := New[int]()
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan bool)
+	go func() {
+		_, loaded := m.Load("a", func() *int {
+			close(started)
+			<-release
+			return new(int)
+		})
+		done <- loaded
+	}()
+	<-started
+	m.Forget("a")
+	close(release)
+	if <-done || m.Get("a") != nil {
+		t.Fatal("published the value of a key forgotten while loading")
+	}
+	m.Load("a", func() *int { return new(int) })
+	m.Forget("a")
+	if m.Get("a") != nil {
+		t.Fatal("expected the value forgotten")
+	}
+}
+
+// The benchmarks read loaded keys while another key keeps loading for a millisecond,
+// as the repositories did holding their lock, and as they do now.
+
+var keys = func() (ret []string) {
+	for i := 0; i < 64; i++ {
+		ret = append(ret, fmt.Sprint(i))
+	}
+	return
+}()
+
+func BenchmarkReadLocked(b *testing.B) {
+	var mut sync.RWMutex
+	values := make(map[string]*int)
+	for _, key := range keys {
+		values[key] = new(int)
+	}
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			mut.Lock()
+			time.Sleep(time.Millisecond)
+			values["loading"] = new(int)
+			mut.Unlock()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			mut.RLock()
+			_ = values[keys[i%len(keys)]]
+			mut.RUnlock()
+			i++
+		}
+	})
+	close(stop)
+}
+
+func BenchmarkReadSnapshot(b *testing.B) {
+	m := New[int]()
+	for _, key := range keys {
+		m.Load(key, func() *int { return new(int) })
+	}
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			m.Load("loading", func() *int {
+				time.Sleep(time.Millisecond)
+				return new(int)
+			})
+			m.Forget("loading")
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			_ = m.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+	close(stop)
+}
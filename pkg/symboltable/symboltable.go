@@ -0,0 +1,242 @@
+// Package symboltable maps IPA phone strings to the integer phoneme IDs consumed
+// by Piper/VITS-style voices.
+//
+// A Table is compatible with the "phoneme_id_map" of a Piper voice config, so the
+// voice config JSON can be used as is. Phones are tokenized by longest match
+// against the map keys. The pad, BOS, EOS and word separator symbols follow the
+// Piper conventions unless overridden.
+package symboltable
+
+import (
+	"errors"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrEmptyTable is returned by Validate when the table maps no symbols.
+var ErrEmptyTable = errors.New("symbol table has no phoneme_id_map")
+
+// Table is a named voice model symbol map with its conventions.
+type Table struct {
+	PhonemeIdMap map[string][]int `json:"phoneme_id_map"`
+
+	Pad           *string `json:",omitempty"`
+	Bos           *string `json:",omitempty"`
+	Eos           *string `json:",omitempty"`
+	WordSeparator *string `json:",omitempty"`
+	NoIntersperse bool    `json:",omitempty"`
+
+	longest int
+}
+
+// Missing reports a phone not present in the table and the symbol used in its place.
+type Missing struct {
+	Phone      string
+	Suggestion string
+}
+
+// Word holds the phone tokens of one word and their IDs.
+type Word struct {
+	Tokens  []string
+	Ids     []int
+	Missing []Missing
+}
+
+func symbol(s *string, def string) string {
+	if s == nil {
+		return def
+	}
+	return *s
+}
+
+// PadSymbol returns the pad symbol, "_" by default.
+func (t *Table) PadSymbol() string {
+	return symbol(t.Pad, "_")
+}
+
+// BosSymbol returns the beginning of sentence symbol, "^" by default.
+func (t *Table) BosSymbol() string {
+	return symbol(t.Bos, "^")
+}
+
+// EosSymbol returns the end of sentence symbol, "$" by default.
+func (t *Table) EosSymbol() string {
+	return symbol(t.Eos, "$")
+}
+
+// WordSeparatorSymbol returns the word separator symbol, " " by default.
+func (t *Table) WordSeparatorSymbol() string {
+	return symbol(t.WordSeparator, " ")
+}
+
+// Validate checks the table and prepares it for tokenization.
+func (t *Table) Validate() error {
+	if len(t.PhonemeIdMap) == 0 {
+		return ErrEmptyTable
+	}
+	t.longest = 0
+	for k := range t.PhonemeIdMap {
+		if n := utf8.RuneCountInString(k); n > t.longest {
+			t.longest = n
+		}
+	}
+	return nil
+}
+
+// ids returns the IDs of a symbol, nil when the table lacks it.
+func (t *Table) ids(sym string) []int {
+	if sym == "" {
+		return nil
+	}
+	return t.PhonemeIdMap[sym]
+}
+
+// grapheme returns the byte length of the base rune and its combining marks at the start of s.
+func grapheme(s string) int {
+	_, size := utf8.DecodeRuneInString(s)
+	for size < len(s) {
+		r, n := utf8.DecodeRuneInString(s[size:])
+		if !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r) {
+			break
+		}
+		size += n
+	}
+	return size
+}
+
+// equivalents lists IPA symbols commonly substituted for one another.
+var equivalents = map[string][]string{
+	"ɡ": {"g"}, "g": {"ɡ"},
+	"ɹ": {"r", "ɾ"}, "ɾ": {"r", "ɹ"}, "ʁ": {"r", "ʀ"}, "ʀ": {"ʁ", "r"}, "r": {"ɹ", "ɾ"},
+	"ɫ": {"l"}, "ɬ": {"l"},
+	"ɐ": {"a", "ə"}, "ɑ": {"a"}, "æ": {"a", "ɛ"}, "ä": {"a"},
+	"ɜ": {"ə"}, "ɚ": {"ə"}, "ɝ": {"ə"},
+	"ɪ": {"i"}, "ʊ": {"u"}, "ɨ": {"i"}, "ʉ": {"u"},
+	"ɔ": {"o"}, "ɒ": {"ɔ", "o"}, "ɛ": {"e"}, "ʌ": {"ə", "a"},
+	"ʤ": {"dʒ"}, "ʧ": {"tʃ"}, "dʒ": {"ʤ"}, "tʃ": {"ʧ"},
+	"ɱ": {"m"}, "ɲ": {"n"}, "ɳ": {"n"},
+}
+
+// Nearest returns the closest symbol the table has for a missing phone, or "".
+func (t *Table) Nearest(phone string) string {
+	var candidates []string
+	candidates = append(candidates, equivalents[phone]...)
+	var base []rune
+	for _, r := range norm.NFD.String(phone) {
+		if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) || unicode.Is(unicode.Lm, r) {
+			continue
+		}
+		base = append(base, r)
+	}
+	if string(base) != phone && len(base) > 0 {
+		candidates = append(candidates, string(base))
+		candidates = append(candidates, equivalents[string(base)]...)
+		for _, r := range base {
+			candidates = append(candidates, string(r))
+			candidates = append(candidates, equivalents[string(r)]...)
+		}
+	}
+	for _, c := range candidates {
+		if c != phone && len(t.PhonemeIdMap[c]) > 0 {
+			return c
+		}
+	}
+	return ""
+}
+
+// Phones tokenizes an IPA string by longest match against the table keys.
+// Graphemes not in the table are reported together with their nearest symbol,
+// which is used in their place when there is one.
+func (t *Table) Phones(ipa string) (w Word) {
+	longest := t.longest
+	if longest == 0 {
+		for k := range t.PhonemeIdMap {
+			longest = max(longest, utf8.RuneCountInString(k))
+		}
+	}
+	for i := 0; i < len(ipa); {
+		var end, n int
+		for j := i; j < len(ipa) && n < longest; n++ {
+			_, size := utf8.DecodeRuneInString(ipa[j:])
+			j += size
+			if len(t.PhonemeIdMap[ipa[i:j]]) > 0 {
+				end = j
+			}
+		}
+		if end > 0 {
+			w.Tokens = append(w.Tokens, ipa[i:end])
+			w.Ids = append(w.Ids, t.PhonemeIdMap[ipa[i:end]]...)
+			i = end
+			continue
+		}
+		end = i + grapheme(ipa[i:])
+		phone := ipa[i:end]
+		i = end
+		near := t.Nearest(phone)
+		w.Missing = append(w.Missing, Missing{Phone: phone, Suggestion: near})
+		if near != "" {
+			w.Tokens = append(w.Tokens, near)
+			w.Ids = append(w.Ids, t.PhonemeIdMap[near]...)
+		}
+	}
+	return
+}
+
+// Sentence joins the words into one ID sequence: BOS, the phones of the words
+// split by the word separator, then EOS. Unless disabled, the pad is
+// interspersed after BOS and after every phone, the same way Piper does.
+func (t *Table) Sentence(words []Word, punct [][2]string) (ids []int) {
+	pad := t.ids(t.PadSymbol())
+	if t.NoIntersperse {
+		pad = nil
+	}
+	push := func(sym []int) {
+		if len(sym) == 0 {
+			return
+		}
+		ids = append(ids, sym...)
+		ids = append(ids, pad...)
+	}
+	push(t.ids(t.BosSymbol()))
+	pushPunct := func(s string) {
+		for _, r := range s {
+			push(t.ids(string(r)))
+		}
+	}
+	for i, word := range words {
+		if i > 0 {
+			push(t.ids(t.WordSeparatorSymbol()))
+		}
+		if i < len(punct) {
+			pushPunct(punct[i][0])
+		}
+		for _, token := range word.Tokens {
+			push(t.ids(token))
+		}
+		if i < len(punct) {
+			pushPunct(punct[i][1])
+		}
+	}
+	if eos := t.ids(t.EosSymbol()); len(eos) > 0 {
+		ids = append(ids, eos...)
+	}
+	return
+}
+
+// Symbols returns the table keys in ID order, useful for listing a table.
+func (t *Table) Symbols() (ret []string) {
+	for k := range t.PhonemeIdMap {
+		ret = append(ret, k)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := t.PhonemeIdMap[ret[i]], t.PhonemeIdMap[ret[j]]
+		if len(a) > 0 && len(b) > 0 && a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return ret[i] < ret[j]
+	})
+	return
+}
@@ -0,0 +1,73 @@
+package symboltable
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const piperConfig = `{
+	"audio": {"sample_rate": 22050},
+	"phoneme_id_map": {
+		"_": [0], "^": [1], "$": [2], " ": [3], ",": [4],
+		"a": [10], "t": [11], "ʃ": [12], "tʃ": [13], "ˈ": [14], "r": [15], "i": [16]
+	}
+}`
+
+func mustTable(t *testing.T) *Table {
+	t.Helper()
+	var table Table
+	if err := json.Unmarshal([]byte(piperConfig), &table); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := table.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	return &table
+}
+
+func TestPhonesLongestMatch(t *testing.T) {
+	word := mustTable(t).Phones("ˈtʃat")
+	if got, want := word.Tokens, []string{"ˈ", "tʃ", "a", "t"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected tokens %v, got %v", want, got)
+	}
+	if got, want := word.Ids, []int{14, 13, 10, 11}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected ids %v, got %v", want, got)
+	}
+	if len(word.Missing) != 0 {
+		t.Fatalf("expected no missing phones, got %v", word.Missing)
+	}
+}
+
+func TestPhonesMissingSuggestion(t *testing.T) {
+	word := mustTable(t).Phones("ɹɪã")
+	want := []Missing{{"ɹ", "r"}, {"ɪ", "i"}, {"ã", "a"}}
+	if !reflect.DeepEqual(word.Missing, want) {
+		t.Fatalf("expected missing %v, got %v", want, word.Missing)
+	}
+	if got, want := word.Ids, []int{15, 16, 10}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected ids %v, got %v", want, got)
+	}
+}
+
+func TestSentenceConventions(t *testing.T) {
+	table := mustTable(t)
+	words := []Word{table.Phones("at"), table.Phones("a")}
+	ids := table.Sentence(words, [][2]string{{"", ","}, {"", ""}})
+	want := []int{1, 0, 10, 0, 11, 0, 4, 0, 3, 0, 10, 0, 2}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	table.NoIntersperse = true
+	ids = table.Sentence(words, nil)
+	want = []int{1, 10, 11, 3, 10, 2}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+}
+
+func TestValidateEmpty(t *testing.T) {
+	if err := (&Table{}).Validate(); err != ErrEmptyTable {
+		t.Fatalf("expected ErrEmptyTable, got %v", err)
+	}
+}
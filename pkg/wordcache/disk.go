@@ -0,0 +1,292 @@
+package wordcache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// DefaultDiskLimit is the bound of the cache file in bytes when it is not configured.
+const DefaultDiskLimit = 64 << 20
+
+// record is a line of the cache file: a word with its parts, or a language whose words
+// are dropped from the lines before it.
+type record struct {
+	Key    *Key   `json:",omitempty"`
+	Parts  Parts  `json:",omitempty"`
+	Forget string `json:",omitempty"`
+}
+
+// span is where the line of a word is in the cache file.
+type span struct {
+	offset int64
+	length int64
+}
+
+// Disk is the persistent tier of the cache, an append-only file with a line of JSON per
+// word. The words are indexed in memory by their offsets and read on demand. When the
+// file grows beyond its limit, it is compacted to the newest words filling half of it.
+// A nil disk keeps nothing.
+type Disk struct {
+	mut   sync.Mutex
+	path  string
+	file  *os.File
+	limit int64
+	size  int64
+	index map[Key]span
+
+	hits   int64
+	misses int64
+}
+
+// OpenDisk opens the cache file, creating it when missing, bounded to limit bytes, zero
+// takes the default. A line torn by a crash ends the file and is cut off.
+func OpenDisk(path string, limit int64) (*Disk, error) {
+	if limit <= 0 {
+		limit = DefaultDiskLimit
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	d := &Disk{path: path, file: file, limit: limit}
+	if err := d.scan(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// scan indexes the lines of the file and cuts off a torn last line.
+func (d *Disk) scan() error {
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	d.index = make(map[Key]span)
+	d.size = 0
+	reader := bufio.NewReader(d.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var r record
+		if json.Unmarshal(line, &r) != nil {
+			break
+		}
+		d.apply(r, span{offset: d.size, length: int64(len(line))})
+		d.size += int64(len(line))
+	}
+	return d.file.Truncate(d.size)
+}
+
+// apply adds the record found at the span to the index.
+func (d *Disk) apply(r record, at span) {
+	if r.Forget != "" {
+		d.forget(r.Forget)
+	}
+	if r.Key != nil {
+		d.index[*r.Key] = at
+	}
+}
+
+// forget drops the words using the language from the index, it reports whether there were any.
+func (d *Disk) forget(lang string) (found bool) {
+	for key := range d.index {
+		if key.uses(lang) {
+			delete(d.index, key)
+			found = true
+		}
+	}
+	return
+}
+
+// read returns the parts at the span.
+func (d *Disk) read(at span) (Parts, error) {
+	line := make([]byte, at.length)
+	if _, err := d.file.ReadAt(line, at.offset); err != nil {
+		return nil, err
+	}
+	var r record
+	if err := json.Unmarshal(line, &r); err != nil {
+		return nil, err
+	}
+	return r.Parts, nil
+}
+
+// write appends the record to the file.
+func (d *Disk) write(r record) (at span, err error) {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return at, err
+	}
+	line = append(line, '\n')
+	if _, err := d.file.WriteAt(line, d.size); err != nil {
+		return at, err
+	}
+	at = span{offset: d.size, length: int64(len(line))}
+	d.size += at.length
+	return at, nil
+}
+
+// Load returns the parts of the word, nil when the word is not in the file.
+func (d *Disk) Load(key Key) Parts {
+	if d == nil {
+		return nil
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	at, ok := d.index[key]
+	if !ok {
+		d.misses++
+		return nil
+	}
+	parts, err := d.read(at)
+	if err != nil {
+		d.misses++
+		return nil
+	}
+	d.hits++
+	return parts
+}
+
+// Store appends the parts of the word to the file, unless it is there already. An empty
+// result is not kept.
+func (d *Disk) Store(key Key, parts Parts) error {
+	if d == nil || len(parts) == 0 {
+		return nil
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if _, ok := d.index[key]; ok {
+		return nil
+	}
+	at, err := d.write(record{Key: &key, Parts: parts})
+	if err != nil {
+		return err
+	}
+	d.index[key] = at
+	if d.size > d.limit {
+		return d.compact()
+	}
+	return nil
+}
+
+// Forget drops the words whose result depends on the language, in both directions.
+func (d *Disk) Forget(lang string) error {
+	if d == nil {
+		return nil
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if !d.forget(lang) {
+		return nil
+	}
+	_, err := d.write(record{Forget: lang})
+	return err
+}
+
+// newest returns the keys of the words, the newest first.
+func (d *Disk) newest() []Key {
+	keys := make([]Key, 0, len(d.index))
+	for key := range d.index {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return d.index[keys[i]].offset > d.index[keys[j]].offset
+	})
+	return keys
+}
+
+// compact rewrites the file with the newest words filling half of the limit.
+func (d *Disk) compact() error {
+	var keep []Key
+	var size int64
+	for _, key := range d.newest() {
+		if size += d.index[key].length; size > d.limit/2 {
+			break
+		}
+		keep = append(keep, key)
+	}
+	tmp, err := os.Create(d.path + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	writer := bufio.NewWriter(tmp)
+	for i := len(keep) - 1; i >= 0; i-- {
+		at := d.index[keep[i]]
+		line := make([]byte, at.length)
+		if _, err := d.file.ReadAt(line, at.offset); err != nil {
+			tmp.Close()
+			return err
+		}
+		writer.Write(line)
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), d.path); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(d.path, os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	d.file.Close()
+	d.file = file
+	return d.scan()
+}
+
+// Warm calls fill with the newest n words, the oldest of them first.
+func (d *Disk) Warm(n int, fill func(key Key, parts Parts)) {
+	if d == nil {
+		return
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	keys := d.newest()
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if parts, err := d.read(d.index[keys[i]]); err == nil {
+			fill(keys[i], parts)
+		}
+	}
+}
+
+// Stats returns the hits and the misses of the file, and the words and the bytes in it.
+func (d *Disk) Stats() (s Stats) {
+	if d == nil {
+		return
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return Stats{Hits: d.hits, Misses: d.misses, Words: int64(len(d.index)), Bytes: d.size}
+}
+
+// Close closes the file.
+func (d *Disk) Close() error {
+	if d == nil {
+		return nil
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return d.file.Close()
+}
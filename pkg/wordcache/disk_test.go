@@ -0,0 +1,108 @@
+package wordcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiskReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.jsonl")
+	d, err := OpenDisk(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key{Language: "English", Model: "abc", Word: "don't"}
+	parts := Parts{{"don": 0, "doʊn": 1}, {"'t": 0, "t": 3}}
+	if err := d.Store(key, parts); err != nil {
+		t.Fatal(err)
+	}
+	d.Store(Key{Language: "Czech", Model: "abc", Word: "a"}, Parts{{"a": 0, "ä": 1}})
+	d.Close()
+
+	// a line torn by a crash
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	file.WriteString(`{"Key":{"Word":"torn"`)
+	file.Close()
+
+	if d, err = OpenDisk(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if got := d.Load(key); !reflect.DeepEqual(got, parts) {
+		t.Fatalf("expected %v, got %v", parts, got)
+	}
+	if got := d.Load(Key{Language: "English", Model: "abd", Word: "don't"}); got != nil {
+		t.Fatalf("expected nothing of another model, got %v", got)
+	}
+	if s := d.Stats(); s.Hits != 1 || s.Misses != 1 || s.Words != 2 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	d.Store(Key{Word: "after"}, Parts{{"after": 0}})
+	if d.Load(Key{Word: "after"}) == nil {
+		t.Fatal("expected the word stored after the torn line")
+	}
+}
+
+func TestDiskForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.jsonl")
+	d, _ := OpenDisk(path, 0)
+	english := Key{Language: "English", Languages: "German", Word: "a"}
+	czech := Key{Language: "Czech", Word: "a"}
+	d.Store(english, Parts{{"a": 0}})
+	d.Store(czech, Parts{{"a": 0}})
+	d.Forget("German")
+	d.Close()
+	d, _ = OpenDisk(path, 0)
+	defer d.Close()
+	if d.Load(english) != nil || d.Load(czech) == nil {
+		t.Fatal("expected only the words using German forgotten")
+	}
+	d.Store(english, Parts{{"a": 0}})
+	if d.Load(english) == nil {
+		t.Fatal("expected the word stored again")
+	}
+}
+
+func TestDiskCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.jsonl")
+	d, _ := OpenDisk(path, 4096)
+	var keys []Key
+	for i := 0; i < 200; i++ {
+		key := Key{Language: "English", Model: "abc", Word: fmt.Sprint("word", i)}
+		keys = append(keys, key)
+		if err := d.Store(key, Parts{{key.Word: 0, "wɝd": 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := d.Stats()
+	if s.Bytes > 4096 {
+		t.Fatalf("expected at most 4096 bytes, got %d", s.Bytes)
+	}
+	if d.Load(keys[0]) != nil || d.Load(keys[199]) == nil {
+		t.Fatal("expected the oldest words dropped and the newest kept")
+	}
+	d.Close()
+	d, _ = OpenDisk(path, 4096)
+	defer d.Close()
+	if d.Stats().Words != s.Words {
+		t.Fatalf("expected %d words, got %d", s.Words, d.Stats().Words)
+	}
+	var warmed []Key
+	d.Warm(3, func(key Key, parts Parts) {
+		warmed = append(warmed, key)
+	})
+	if !reflect.DeepEqual(warmed, keys[197:]) {
+		t.Fatalf("expected the newest words warmed, got %v", warmed)
+	}
+}
+
+func TestDiskNil(t *testing.T) {
+	var d *Disk
+	d.Store(Key{Word: "a"}, Parts{{"a": 0}})
+	if d.Load(Key{Word: "a"}) != nil || d.Forget("English") != nil || d.Stats() != (Stats{}) {
+		t.Fatal("a nil disk kept a word")
+	}
+}
@@ -0,0 +1,144 @@
+// Package wordcache keeps the words the model phonemized. A word is keyed by everything
+// its result depends on, the direction, the languages and the version of the model, so a
+// reloaded model never answers from the results of the one it replaced. The results are
+// copied in and out, the caller may change them freely.
+package wordcache
+
+import (
+	"github.com/maypok86/otter"
+	"strings"
+	"time"
+)
+
+// Default is the capacity in words, and DefaultTTL how long a word is kept, when they are not configured.
+const (
+	Default    = 10_000
+	DefaultTTL = time.Hour
+)
+
+// Key identifies the result of the model for a word.
+type Key struct {
+	Reverse  bool
+	Language string
+	// Languages are the fallback languages of the request, joined by commas.
+	Languages string
+	// Model is the version of the model, the checksum of its weights.
+	Model string
+	Word  string
+}
+
+// uses reports whether the result depends on the language.
+func (k Key) uses(lang string) bool {
+	return k.Language == lang || strings.Contains(","+k.Languages+",", ","+lang+",")
+}
+
+// Parts is the result of the model for a word, one map per word the result splits into,
+// with the source spelling under zero and the pronunciations under their tags.
+type Parts []map[string]uint32
+
+// clone copies the parts deeply.
+func (p Parts) clone() (ret Parts) {
+	if p == nil {
+		return nil
+	}
+	ret = make(Parts, len(p))
+	for i, part := range p {
+		ret[i] = make(map[string]uint32, len(part))
+		for k, v := range part {
+			ret[i][k] = v
+		}
+	}
+	return
+}
+
+// Cache is a bounded cache of the words with a time to live. A nil cache keeps nothing.
+type Cache struct {
+	cache otter.Cache[Key, Parts]
+}
+
+// New creates a cache of at most size words, each kept for ttl. A size below zero
+// disables the cache, zero size or ttl take the defaults.
+func New(size int, ttl time.Duration) (*Cache, error) {
+	if size < 0 {
+		return nil, nil
+	}
+	if size == 0 {
+		size = Default
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	cache, err := otter.MustBuilder[Key, Parts](size).
+		CollectStats().
+		Cost(func(key Key, value Parts) uint32 {
+			return 1
+		}).
+		WithTTL(ttl).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{cache: cache}, nil
+}
+
+// Load returns a copy of the parts of the word, nil when the word is not cached.
+func (c *Cache) Load(key Key) Parts {
+	if c == nil {
+		return nil
+	}
+	parts, _ := c.cache.Get(key)
+	return parts.clone()
+}
+
+// Store keeps a copy of the parts of the word. An empty result is not kept.
+func (c *Cache) Store(key Key, parts Parts) {
+	if c == nil || len(parts) == 0 {
+		return
+	}
+	c.cache.Set(key, parts.clone())
+}
+
+// Forget drops the words whose result depends on the language, in both directions.
+func (c *Cache) Forget(lang string) {
+	if c == nil {
+		return
+	}
+	c.cache.DeleteByFunc(func(key Key, _ Parts) bool {
+		return key.uses(lang)
+	})
+}
+
+// Stats are the counters of a tier of the cache.
+type Stats struct {
+	Hits   int64
+	Misses int64
+	// Words is the number of the cached words.
+	Words int64
+	// Bytes is the size of the cache file, zero in memory.
+	Bytes int64
+}
+
+// Stats returns the hits and the misses of the cache, and the words in it.
+func (c *Cache) Stats() (s Stats) {
+	if c == nil {
+		return
+	}
+	stats := c.cache.Stats()
+	return Stats{Hits: stats.Hits(), Misses: stats.Misses(), Words: int64(c.cache.Size())}
+}
+
+// Capacity returns the capacity of the cache in words.
+func (c *Cache) Capacity() int {
+	if c == nil {
+		return 0
+	}
+	return c.cache.Capacity()
+}
+
+// Len returns the number of the cached words.
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	return c.cache.Size()
+}
@@ -0,0 +1,84 @@
+package wordcache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	c, err := New(100, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key{Language: "English", Languages: "English,German", Model: "abc", Word: "don't"}
+	parts := Parts{
+		{"don": 0, "doʊn": 1, "dɒn": 2},
+		{"'t": 0, "t": 3},
+	}
+	c.Store(key, parts)
+	got := c.Load(key)
+	if !reflect.DeepEqual(got, parts) {
+		t.Fatalf("expected %v, got %v", parts, got)
+	}
+	got[0]["don"] = 9
+	parts[1]["t"] = 9
+	if again := c.Load(key); again[0]["don"] != 0 || again[1]["t"] != 3 {
+		t.Fatalf("the cached parts changed: %v", again)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	c, _ := New(100, time.Minute)
+	key := Key{Language: "English", Model: "abc", Word: "read"}
+	c.Store(key, Parts{{"read": 0, "ɹiːd": 1}})
+	for _, other := range []Key{
+		{Reverse: true, Language: "English", Model: "abc", Word: "read"},
+		{Language: "German", Model: "abc", Word: "read"},
+		{Language: "English", Languages: "German", Model: "abc", Word: "read"},
+		{Language: "English", Model: "abd", Word: "read"},
+		{Language: "English", Model: "abc", Word: "Read"},
+	} {
+		if got := c.Load(other); got != nil {
+			t.Errorf("%+v: expected nothing, got %v", other, got)
+		}
+	}
+	c.Store(Key{Word: "empty"}, Parts{})
+	if c.Len() != 1 {
+		t.Fatalf("expected one word, got %d", c.Len())
+	}
+}
+
+func TestForget(t *testing.T) {
+	c, _ := New(100, time.Minute)
+	english := Key{Language: "English", Languages: "English,German", Word: "a"}
+	reverse := Key{Reverse: true, Language: "English", Word: "a"}
+	czech := Key{Language: "Czech", Languages: "Czech,Slovak", Word: "a"}
+	for _, key := range []Key{english, reverse, czech} {
+		c.Store(key, Parts{{"a": 0, "ə": 1}})
+	}
+	c.Forget("German")
+	if c.Load(english) != nil || c.Load(reverse) == nil {
+		t.Fatal("expected only the words using German forgotten")
+	}
+	c.Forget("English")
+	if c.Load(reverse) != nil || c.Load(czech) == nil {
+		t.Fatal("expected the words of English forgotten")
+	}
+	c.Forget("Slovak")
+	if c.Len() != 0 {
+		t.Fatalf("expected no words, got %d", c.Len())
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	c, err := New(-1, 0)
+	if c != nil || err != nil {
+		t.Fatalf("expected a nil cache, got %v %v", c, err)
+	}
+	c.Store(Key{Word: "a"}, Parts{{"a": 0}})
+	c.Forget("English")
+	if c.Load(Key{Word: "a"}) != nil || c.Len() != 0 {
+		t.Fatal("a disabled cache kept a word")
+	}
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"encoding/json"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"sort"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ICliticRepository interface {
+	// Clitics returns the clitic rules of the language, longest affixes first, nil when it has none.
+	Clitics(isReverse bool, lang string) []Clitic
+}
+type CliticRepository struct {
+	getter *interfaces.DictGetter
+
+	mut  *sync.RWMutex
+	lang *cliticlanguages
+}
+
+type cliticlanguages map[string]*cliticlanguage
+
+type cliticlanguage struct {
+	Clitics []Clitic `json:"Clitics"`
+}
+
+// Clitic describes a prefix or a suffix attached to words of a language.
+type Clitic struct {
+	// Prefix is the spelling of a proclitic, such as "l'".
+	Prefix string `json:"Prefix"`
+	// Suffix is the spelling of an enclitic, such as "'s".
+	Suffix string `json:"Suffix"`
+	// Ipa is the pronunciation of the clitic.
+	Ipa string `json:"Ipa"`
+	// Separately phonemizes the clitic, apostrophes removed, as a word of its own instead of using Ipa.
+	Separately bool `json:"Separately"`
+	// AnyStem allows stems missing from the lexicon, these are phonemized by the model.
+	AnyStem bool `json:"AnyStem"`
+	// Sandhi overrides Ipa next to the listed phones of the stem, the first matching entry wins.
+	Sandhi []Sandhi `json:"Sandhi"`
+}
+
+// Sandhi is the pronunciation of a clitic next to some phones of the stem.
+type Sandhi struct {
+	// Phones lists the first phones (for prefixes) or last phones (for suffixes) of the stem.
+	Phones []string `json:"Phones"`
+	// Ipa is the pronunciation of the clitic, "{phone}" is replaced by the matched phone.
+	Ipa string `json:"Ipa"`
+}
+
+// Affix returns the spelling of the clitic.
+func (c *Clitic) Affix() string {
+	return c.Prefix + c.Suffix
+}
+
+func (s *CliticRepository) Clitics(isReverse bool, lang string) []Clitic {
+	s.LoadLanguage(isReverse, lang)
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	language := (*s.lang)[lang+reverse]
+	if language == nil {
+		return nil
+	}
+	return language.Clitics
+}
+
+func (p *CliticRepository) LoadLanguage(isReverse bool, lang string) {
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+
+	p.mut.RLock()
+	existing_lang := (*p.lang)[lang+reverse]
+	p.mut.RUnlock()
+
+	if existing_lang != nil {
+		return
+	}
+
+	var language_files = []string{"language" + reverse + ".json"}
+	for _, file := range language_files {
+		log.Now().Debugf("Language %s loading file", file)
+		data := log.Error1((*p.getter).GetDict(lang, file))
+
+		var langone cliticlanguage
+		err := json.Unmarshal(data, &langone)
+		if err != nil {
+			log.Now().Errorf("Error parsing JSON: %v\n", err)
+			continue
+		}
+		var clitics []Clitic
+		for _, clitic := range langone.Clitics {
+			if (clitic.Prefix == "") == (clitic.Suffix == "") {
+				log.Now().Errorf("Clitic needs either a prefix or a suffix: %v", clitic)
+				continue
+			}
+			clitics = append(clitics, clitic)
+		}
+		sort.SliceStable(clitics, func(i, j int) bool {
+			return len(clitics[i].Affix()) > len(clitics[j].Affix())
+		})
+		langone.Clitics = clitics
+		p.mut.Lock()
+		(*p.lang)[lang+reverse] = &langone
+		p.mut.Unlock()
+	}
+}
+
+func NewCliticRepository(di *DependencyInjection) *CliticRepository {
+	getter := MustAny[interfaces.DictGetter](di)
+	langs := make(cliticlanguages)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(langs, key)
+	})
+
+	return &CliticRepository{
+		getter: &getter,
+		lang:   &langs,
+		mut:    mut,
+	}
+}
+
+var _ ICliticRepository = &CliticRepository{}
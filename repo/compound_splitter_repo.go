@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"encoding/json"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ICompoundSplitterRepository interface {
+	// Compound returns the decompounding rules of the language, nil when it has none.
+	Compound(isReverse bool, lang string) *Compound
+}
+type CompoundSplitterRepository struct {
+	getter *interfaces.DictGetter
+
+	mut  *sync.RWMutex
+	lang *compoundlanguages
+}
+
+type compoundlanguages map[string]*compoundlanguage
+
+type compoundlanguage struct {
+	Compound *Compound `json:"Compound"`
+}
+
+// Compound describes how words of a language are joined into compounds.
+type Compound struct {
+	// LinkingElements maps a linking element, which may join two parts, to its IPA.
+	LinkingElements map[string]string `json:"LinkingElements"`
+	// MinPart is the minimal length of a part in letters.
+	MinPart int `json:"MinPart"`
+	// MaxParts is the maximal number of parts.
+	MaxParts int `json:"MaxParts"`
+}
+
+func (c *Compound) load() {
+	if c.MinPart <= 0 {
+		c.MinPart = 3
+	}
+	if c.MaxParts <= 0 {
+		c.MaxParts = 4
+	}
+}
+
+func (s *CompoundSplitterRepository) Compound(isReverse bool, lang string) *Compound {
+	s.LoadLanguage(isReverse, lang)
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	language := (*s.lang)[lang+reverse]
+	if language == nil {
+		return nil
+	}
+	return language.Compound
+}
+
+func (p *CompoundSplitterRepository) LoadLanguage(isReverse bool, lang string) {
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+
+	p.mut.RLock()
+	existing_lang := (*p.lang)[lang+reverse]
+	p.mut.RUnlock()
+
+	if existing_lang != nil {
+		return
+	}
+
+	var language_files = []string{"language" + reverse + ".json"}
+	for _, file := range language_files {
+		log.Now().Debugf("Language %s loading file", file)
+		data := log.Error1((*p.getter).GetDict(lang, file))
+
+		var langone compoundlanguage
+		err := json.Unmarshal(data, &langone)
+		if err != nil {
+			log.Now().Errorf("Error parsing JSON: %v\n", err)
+			continue
+		}
+		if langone.Compound != nil {
+			langone.Compound.load()
+		}
+		p.mut.Lock()
+		(*p.lang)[lang+reverse] = &langone
+		p.mut.Unlock()
+	}
+}
+
+func NewCompoundSplitterRepository(di *DependencyInjection) *CompoundSplitterRepository {
+	getter := MustAny[interfaces.DictGetter](di)
+	langs := make(compoundlanguages)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(langs, key)
+	})
+
+	return &CompoundSplitterRepository{
+		getter: &getter,
+		lang:   &langs,
+		mut:    mut,
+	}
+}
+
+var _ ICompoundSplitterRepository = &CompoundSplitterRepository{}
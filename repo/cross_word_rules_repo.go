@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"encoding/json"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/crossword"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ICrossWordRulesRepository interface {
+	// CrossWordRules returns the compiled cross-word rules of the language, nil when it has none.
+	CrossWordRules(isReverse bool, lang string) []crossword.Rule
+}
+type CrossWordRulesRepository struct {
+	getter *interfaces.DictGetter
+
+	mut  *sync.RWMutex
+	lang *crosswordlanguages
+}
+
+type crosswordlanguages map[string]*crosswordlanguage
+
+type crosswordlanguage struct {
+	CrossWord []crossword.Rule `json:"CrossWord"`
+}
+
+func (s *CrossWordRulesRepository) CrossWordRules(isReverse bool, lang string) []crossword.Rule {
+	s.LoadLanguage(isReverse, lang)
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	language := (*s.lang)[lang+reverse]
+	if language == nil {
+		return nil
+	}
+	return language.CrossWord
+}
+
+func (p *CrossWordRulesRepository) LoadLanguage(isReverse bool, lang string) {
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+
+	p.mut.RLock()
+	existing_lang := (*p.lang)[lang+reverse]
+	p.mut.RUnlock()
+
+	if existing_lang != nil {
+		return
+	}
+
+	var language_files = []string{"language" + reverse + ".json"}
+	for _, file := range language_files {
+		log.Now().Debugf("Language %s loading file", file)
+		data := log.Error1((*p.getter).GetDict(lang, file))
+
+		var langone crosswordlanguage
+		err := json.Unmarshal(data, &langone)
+		if err != nil {
+			log.Now().Errorf("Error parsing JSON: %v\n", err)
+			continue
+		}
+		var rules []crossword.Rule
+		for _, rule := range langone.CrossWord {
+			if err := rule.Compile(); err != nil {
+				log.Now().Errorf("Language %s: %v", lang, err)
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		langone.CrossWord = rules
+		p.mut.Lock()
+		(*p.lang)[lang+reverse] = &langone
+		p.mut.Unlock()
+	}
+}
+
+func NewCrossWordRulesRepository(di *DependencyInjection) *CrossWordRulesRepository {
+	getter := MustAny[interfaces.DictGetter](di)
+	langs := make(crosswordlanguages)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(langs, key)
+	})
+
+	return &CrossWordRulesRepository{
+		getter: &getter,
+		lang:   &langs,
+		mut:    mut,
+	}
+}
+
+var _ ICrossWordRulesRepository = &CrossWordRulesRepository{}
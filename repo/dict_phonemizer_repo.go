@@ -3,14 +3,14 @@ package repo
 import (
 	"bytes"
 	"compress/zlib"
-	"encoding/csv"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"github.com/neurlang/classifier/hash"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/lexicon"
 	"github.com/neurlang/goruut/repo/interfaces"
 	"sort"
-	"strings"
 	"sync"
 )
 import . "github.com/martinarisk/di/dependency_injection"
@@ -18,13 +18,20 @@ import . "github.com/martinarisk/di/dependency_injection"
 type IDictPhonemizerRepository interface {
 	LookupWords(isReverse bool, lang string, word string) []map[string]uint32
 	LookupTags(isReverse bool, lang string, word1, word2 string) string
+	LookupOrder(isReverse bool, lang string, word1, word2 string) (order, frequency int)
 }
 type DictPhonemizerRepository struct {
-	getter     *interfaces.DictGetter
-	lang_words *map[string]map[string]map[string]uint32
-	lang_tags  *map[string]map[uint32]string
-	words_tags *map[string]map[[2]string]uint32
-	mut        *sync.RWMutex
+	getter   *interfaces.DictGetter
+	lexicons *map[string]*dictLexicons
+	mut      *sync.RWMutex
+	res      tracker
+}
+
+// dictLexicons are the lexicons of a language, the edits of missing.tsv, or of
+// missing_reverse.tsv, come first and the compiled lexicon follows them.
+type dictLexicons struct {
+	edits *lexicon.Lexicon
+	all   *lexicon.Lexicon
 }
 
 func addTags(bag map[uint32]string, tags ...string) map[uint32]string {
@@ -66,143 +73,107 @@ func serializeTags(tags map[uint32]string) (key uint32, ret string) {
 	return
 }
 
-func (r *DictPhonemizerRepository) LoadLanguage(isReverse bool, lang string) {
+// readTSV compiles the lexicon of a tsv file, each record tagged as dict.
+func readTSV(data []byte, compressed bool) (*lexicon.Lexicon, error) {
+	var b lexicon.Builder
+	if compressed {
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if err := b.ReadTSV(reader, "dict"); err != nil {
+			return nil, err
+		}
+	} else if err := b.ReadTSV(bytes.NewReader(data), "dict"); err != nil {
+		return nil, err
+	}
+	return b.Lexicon(), nil
+}
+
+// loadAll reads the compiled lexicon of the language. When the files have a source
+// lexicon the compiled one was not made of, such as an edited missing.all.zlib in a
+// directory of the loader, the source is compiled instead.
+func (r *DictPhonemizerRepository) loadAll(lang string) *lexicon.Lexicon {
+	compiled, _ := (*r.getter).GetDict(lang, lexicon.FileName)
+	source, _ := (*r.getter).GetDict(lang, lexicon.SourceName)
+	if len(compiled) > 0 {
+		l, err := lexicon.Read(compiled)
+		if err != nil {
+			log.Now().Errorf("Language %s lexicon %s: %v", lang, lexicon.FileName, err)
+		} else if len(source) == 0 || l.Source() == sha256.Sum256(source) {
+			return l
+		}
+	}
+	if len(source) > 0 {
+		log.Now().Infof("Language %s compiling its lexicon %s", lang, lexicon.SourceName)
+		l, err := readTSV(source, true)
+		if err == nil {
+			return l
+		}
+		log.Now().Errorf("Language %s lexicon %s: %v", lang, lexicon.SourceName, err)
+	}
+	return (&lexicon.Builder{}).Lexicon()
+}
+
+func (r *DictPhonemizerRepository) LoadLanguage(isReverse bool, lang string) *dictLexicons {
 	var reverse string
 	if isReverse {
 		reverse = "_reverse"
 	}
 	r.mut.RLock()
-	if (*r.lang_words)[lang+reverse] == nil {
-		r.mut.RUnlock()
-	} else {
-		log.Now().Debugf("Language %s already loaded", lang)
-		r.mut.RUnlock()
-		return
+	lex := (*r.lexicons)[lang+reverse]
+	r.mut.RUnlock()
+	if lex != nil {
+		return lex
 	}
 
-	r.mut.Lock()
-	defer r.mut.Unlock()
-	if (*r.lang_words)[lang+reverse] == nil {
-		(*r.lang_words)[lang+reverse] = make(map[string]map[string]uint32)
-	} else {
-		log.Now().Debugf("Language %s already loaded", lang)
-		return
+	lex = &dictLexicons{all: r.loadAll(lang)}
+	if edits := log.Error1((*r.getter).GetDict(lang, "missing"+reverse+".tsv")); len(edits) > 0 {
+		lex.edits = log.Error1(readTSV(edits, false))
 	}
-	if (*r.lang_tags)[lang+reverse] == nil {
-		(*r.lang_tags)[lang+reverse] = make(map[uint32]string)
-	} else {
-		log.Now().Debugf("Language %s already loaded", lang)
-		return
-	}
-	if (*r.words_tags)[lang+reverse] == nil {
-		(*r.words_tags)[lang+reverse] = make(map[[2]string]uint32)
-	} else {
-		log.Now().Debugf("Language %s already loaded", lang)
-		return
+	if lex.edits == nil {
+		lex.edits = (&lexicon.Builder{}).Lexicon()
 	}
+	log.Now().Debugf("Language %s has %d records", lang, lex.edits.Records()+lex.all.Records())
 
-	var files = []string{"missing" + reverse + ".tsv", "missing.all.zlib"}
-
-	for _, file := range files {
-		clean := log.Error1((*r.getter).GetDict(lang, file))
-		var reader *csv.Reader
-
-		if len(clean) == 0 {
-			continue
-		}
-
-		if strings.HasSuffix(file, ".zlib") {
-			reader = csv.NewReader(log.Error1(zlib.NewReader(bytes.NewReader(clean))))
-		} else {
-			reader = csv.NewReader(bytes.NewReader(clean))
-		}
-
-		reader.Comma = '\t'
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if loaded := (*r.lexicons)[lang+reverse]; loaded != nil {
+		return loaded
+	}
+	(*r.lexicons)[lang+reverse] = lex
+	r.res.Loaded(lang, int64(lex.edits.Size()+lex.all.Size()))
+	return lex
+}
 
-		recs := log.Error1(reader.ReadAll())
-		log.Now().Debugf("Language %s has %d records", lang, len(recs))
-		for _, v := range recs {
-			for i := range v {
-				v[i] = strings.ReplaceAll(v[i], " ", "")
-			}
-			var src, dst, tagstr string
-			if len(v) == 2 {
-				if isReverse {
-					src = v[1]
-					dst = v[0]
-				} else {
-					src = v[0]
-					dst = v[1]
-				}
-				tagstr = "[]"
-			} else if len(v) == 3 {
-				if isReverse {
-					src = v[1]
-					dst = v[0]
-				} else {
-					src = v[0]
-					dst = v[1]
-				}
-				tagstr = v[2]
-			} else {
-				log.Now().Debugf("Language %s has wrong number of columns: %d", src, len(v))
-				continue
-			}
-			if src == "dove" {
-				log.Now().Debugf("Loading dove: %s", dst)
-			}
-			var tagkey, tagjson = serializeTags(addTags(parseTags(tagstr), "dict"))
-			if (*r.lang_words)[lang+reverse][src] == nil {
-				(*r.lang_words)[lang+reverse][src] = make(map[string]uint32)
-			} else if m, ok := (*r.lang_words)[lang+reverse][src][dst]; ok {
-				existingTags := parseTags((*r.lang_tags)[lang+reverse][m])
-				var existing []string
-				for _, tag := range existingTags {
-					existing = append(existing, tag)
-				}
-				tagkey, tagjson = serializeTags(addTags(parseTags(tagstr), existing...))
-			}
-			if src == "dove" {
-				log.Now().Debugf("Storing dove: %s as %d", dst, tagkey)
-			}
-			(*r.lang_words)[lang+reverse][src][dst] = tagkey
-			if (*r.lang_tags)[lang+reverse] == nil {
-				(*r.lang_tags)[lang+reverse] = make(map[uint32]string)
-			}
-			(*r.lang_tags)[lang+reverse][tagkey] = tagjson
-			if (*r.words_tags)[lang+reverse] == nil {
-				(*r.words_tags)[lang+reverse] = make(map[[2]string]uint32)
-			}
-			(*r.words_tags)[lang+reverse][[2]string{src, dst}] = tagkey
-		}
+// mergeTags returns the union of the json arrays of tags, with its key.
+func mergeTags(tags1, tags2 string) (key uint32, ret string) {
+	var existing []string
+	for _, tag := range parseTags(tags2) {
+		existing = append(existing, tag)
 	}
+	return serializeTags(addTags(parseTags(tags1), existing...))
 }
 
 func (r *DictPhonemizerRepository) LookupWords(isReverse bool, lang, word string) (ret []map[string]uint32) {
-	r.LoadLanguage(isReverse, lang)
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	r.mut.RLock()
-	found := (*r.lang_words)[lang+reverse][word]
-	// Create a copy of the found map while holding the mutex
-	var foundCopy map[string]uint32
-	if len(found) > 0 {
-		foundCopy = make(map[string]uint32)
-		for k, v := range found {
-			foundCopy[k] = v
+	lex := r.LoadLanguage(isReverse, lang)
+	var m = make(map[string]uint32)
+	var tags = make(map[string]string)
+	for _, l := range []*lexicon.Lexicon{lex.edits, lex.all} {
+		for _, e := range l.Lookup(isReverse, word) {
+			if existing, ok := tags[e.Dst]; ok {
+				m[e.Dst], tags[e.Dst] = mergeTags(existing, e.Tags)
+			} else {
+				m[e.Dst], tags[e.Dst] = e.Key, e.Tags
+			}
 		}
 	}
-	r.mut.RUnlock()
-
-	if len(foundCopy) == 0 {
+	if len(m) == 0 {
 		return nil
 	}
-	var m = make(map[string]uint32)
-	for k, v := range foundCopy {
+	for k, v := range m {
 		log.Now().Debugf("LookupWords Key: %s, Value: %v", k, v)
-		m[k] = v
 	}
 	m[word+" "] = 0
 	ret = append(ret, m)
@@ -210,34 +181,53 @@ func (r *DictPhonemizerRepository) LookupWords(isReverse bool, lang, word string
 }
 
 func (r *DictPhonemizerRepository) LookupTags(isReverse bool, lang string, word1, word2 string) string {
-	r.LoadLanguage(isReverse, lang)
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	r.mut.RLock()
-	// Copy the result while holding the mutex
-	tagKey := (*r.words_tags)[lang+reverse][[2]string{word1, word2}]
-	found := (*r.lang_tags)[lang+reverse][tagKey]
-	r.mut.RUnlock()
-	if found != "" {
-		return found
+	lex := r.LoadLanguage(isReverse, lang)
+	edit, inEdits := lex.edits.Pair(isReverse, word1, word2)
+	all, inAll := lex.all.Pair(isReverse, word1, word2)
+	switch {
+	case inEdits && inAll:
+		_, tags := mergeTags(edit.Tags, all.Tags)
+		return tags
+	case inEdits:
+		return edit.Tags
+	case inAll:
+		return all.Tags
 	}
 	return "[]"
 }
 
+// LookupOrder returns the position of the first lexicon record of the pair and the number
+// of its records. The order is -1 when the pair is not in the lexicon.
+func (r *DictPhonemizerRepository) LookupOrder(isReverse bool, lang string, word1, word2 string) (order, frequency int) {
+	lex := r.LoadLanguage(isReverse, lang)
+	edit, inEdits := lex.edits.Pair(isReverse, word1, word2)
+	all, inAll := lex.all.Pair(isReverse, word1, word2)
+	switch {
+	case inEdits && inAll:
+		return edit.Order, edit.Frequency + all.Frequency
+	case inEdits:
+		return edit.Order, edit.Frequency
+	case inAll:
+		return lex.edits.Records() + all.Order, all.Frequency
+	}
+	return -1, 0
+}
+
 func NewDictPhonemizerRepository(di *DependencyInjection) *DictPhonemizerRepository {
 	getter := MustAny[interfaces.DictGetter](di)
-	mapping := make(map[string]map[string]map[string]uint32)
-	mapping2 := make(map[string]map[uint32]string)
-	mapping3 := make(map[string]map[[2]string]uint32)
+	lexicons := make(map[string]*dictLexicons)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(lexicons, key)
+	})
 
 	return &DictPhonemizerRepository{
-		getter:     &getter,
-		lang_words: &mapping,
-		lang_tags:  &mapping2,
-		words_tags: &mapping3,
-		mut:        &sync.RWMutex{},
+		getter:   &getter,
+		lexicons: &lexicons,
+		mut:      mut,
+		res:      residencyOf(di),
 	}
 }
 
@@ -1,18 +1,15 @@
 package repo
 
 import (
-	"bytes"
 	"fmt"
-	"github.com/neurlang/classifier/layer/crossattention"
-	"github.com/neurlang/classifier/layer/sochastic"
-	"github.com/neurlang/classifier/layer/sum"
 	"github.com/neurlang/classifier/net/feedforward"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/manifest"
+	"github.com/neurlang/goruut/pkg/snapshot"
 	"github.com/neurlang/goruut/repo/interfaces"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 )
 import "github.com/neurlang/classifier/datasets/phonemizer_multi"
 import "github.com/neurlang/classifier/hash"
@@ -20,108 +17,93 @@ import . "github.com/martinarisk/di/dependency_injection"
 
 type IHashtronHomonymSelectorRepository interface {
 	Select(isReverse bool, lang string, sentence []map[string][2]uint32) (ret [][4]uint32)
+	SelectTrace(isReverse bool, lang string, sentence []map[string][2]uint32) (ret [][4]uint32, votes []map[uint32]uint32)
 }
 
 type HashtronHomonymSelectorRepository struct {
 	getter *interfaces.DictGetter
 
-	mut   *sync.RWMutex
-	hlang *hlanguages
-	nets  *map[string]*feedforward.FeedforwardNetwork
+	models *snapshot.Map[homonymModel]
+	res    tracker
 }
 
-type hlanguages map[string]*hlanguage
-type hlanguage struct {
+// homonymModel is the loaded model of a language, immutable once published. The net is
+// nil when the language has no model.
+type homonymModel struct {
+	net  *feedforward.FeedforwardNetwork
+	arch manifest.Architecture
+	// size is the compressed size of the weights, the approximate memory of the net.
+	size int64
 }
 
-func (r *HashtronHomonymSelectorRepository) LoadLanguage(isReverse bool, lang string) {
+// LoadLanguage returns the model of the language, loading it the first time.
+func (r *HashtronHomonymSelectorRepository) LoadLanguage(isReverse bool, lang string) *homonymModel {
 	var reverse string
 	if isReverse {
 		reverse = "_reverse"
 	}
-
-	// First check with read lock to avoid unnecessary write lock
-	r.mut.RLock()
-	if r.nets != nil && (*r.nets)[lang+reverse] != nil {
-		log.Now().Debugf("Language %s already loaded", lang)
-		r.mut.RUnlock()
-		return
-	}
-	r.mut.RUnlock()
-
-	// Double-checked locking: acquire write lock and check again
-	r.mut.Lock()
-	defer r.mut.Unlock()
-
-	nets := r.nets
-	if nets == nil {
-		netss := make(map[string]*feedforward.FeedforwardNetwork)
-		nets = &netss
-		r.nets = &netss
-		log.Now().Debugf("Language %s made map of nets", lang)
-	}
-	if (*nets)[lang+reverse] != nil {
-		log.Now().Debugf("Language %s already loaded", lang)
-		return
+	model, loaded := r.models.Load(lang+reverse, func() *homonymModel {
+		return r.loadModel(reverse, lang)
+	})
+	if loaded && model.net != nil {
+		r.res.Loaded(lang, model.size)
 	}
+	return model
+}
 
+func (r *HashtronHomonymSelectorRepository) loadModel(reverse, lang string) *homonymModel {
 	var files = []string{
 		"weights7" + reverse + ".json.zlib",
 		//"weights5" + reverse + ".json.zlib",
 	}
-	for i, file := range files {
+	for _, file := range files {
 		compressedData := log.Error1((*r.getter).GetDict(lang, file))
 
 		if compressedData == nil {
 			continue
 		}
 		if (*r.getter).IsNewFormat(compressedData) {
-			bytesReader := bytes.NewReader(compressedData)
-
-			switch i {
-			case 0:
-				const fanout1 = 24
-				const fanout2 = 1
-				const fanout3 = 4
-				const fanout4 = 32
-
-				var net feedforward.FeedforwardNetwork
-				net.NewLayer(fanout1*fanout2, 0)
-				for i := 0; i < fanout3; i++ {
-					net.NewCombiner(crossattention.MustNew3(fanout1, fanout2))
-					net.NewLayerPI(fanout1*fanout2, 0, 0)
-					net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4-8*byte(i), uint32(i)))
-					net.NewLayerPI(fanout1*fanout2, 0, 0)
-				}
-				net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4, fanout3))
-				net.NewLayer(fanout1*fanout2, 0)
-				net.NewCombiner(sum.MustNew([]uint{fanout1 * fanout2}, 0))
-				net.NewLayer(1, 0)
-
-				(*r.nets)[lang+reverse] = &net
-
+			net, arch, err := loadModel(*r.getter, lang, file, compressedData)
+			if err != nil {
+				log.Now().Errorf("Language %s model %s: %v", lang, file, err)
+				return &homonymModel{}
 			}
-			err := (*r.nets)[lang+reverse].ReadZlibWeights(bytesReader)
-			log.Error0(err)
-
-			return
+			return &homonymModel{net: net, arch: arch, size: int64(len(compressedData))}
 		}
 	}
+	// a language without a model keeps an empty one, an unknown language nothing
+	if _, err := (*r.getter).GetDict(lang, "language"+reverse+".json"); err != nil {
+		return nil
+	}
+	return &homonymModel{}
 }
 
+// Select asks the model about the choices of every word and returns the accepted ones.
+// The choices are presented and tried in the order of their hash, as in training, the
+// first accepted choice wins.
 func (r *HashtronHomonymSelectorRepository) Select(isReverse bool, lang string, sentence []map[string][2]uint32) (ret [][4]uint32) {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	r.LoadLanguage(isReverse, lang)
-	r.mut.RLock()
-	net := (*r.nets)[lang+reverse]
-	r.mut.RUnlock()
+	return r.sel(isReverse, lang, sentence, nil)
+}
 
-	if net == nil {
+// SelectTrace selects like Select and also returns, for every word, the model vote keyed by the choice hash.
+func (r *HashtronHomonymSelectorRepository) SelectTrace(isReverse bool, lang string, sentence []map[string][2]uint32) (ret [][4]uint32, votes []map[uint32]uint32) {
+	votes = make([]map[uint32]uint32, len(sentence))
+	ret = r.sel(isReverse, lang, sentence, func(i int, choice uint32, pred uint32) {
+		if votes[i] == nil {
+			votes[i] = make(map[uint32]uint32)
+		}
+		votes[i][choice] = pred
+	})
+	return
+}
+
+func (r *HashtronHomonymSelectorRepository) sel(isReverse bool, lang string, sentence []map[string][2]uint32,
+	vote func(i int, choice uint32, pred uint32)) (ret [][4]uint32) {
+	model := r.LoadLanguage(isReverse, lang)
+	if model == nil || model.net == nil {
 		return
 	}
+	net, fanout1 := model.net, model.arch.Fanout1
 
 	var ai_sentence = phonemizer_multi.Sample{
 		Sentence: []phonemizer_multi.Token{},
@@ -165,7 +147,6 @@ func (r *HashtronHomonymSelectorRepository) Select(isReverse bool, lang string,
 			Solution:  sol,
 		})
 	}
-	const fanout1 = 24
 	for i := range ai_sentence.Sentence {
 		var sample = ai_sentence.V2(fanout1, i)
 		if sample.Len() <= 1 {
@@ -184,11 +165,12 @@ func (r *HashtronHomonymSelectorRepository) Select(isReverse bool, lang string,
 				//for feat := 0; feat < fanout1; feat++ {
 				//	fmt.Printf("Sample IO %d %d: %d\n", i, j, sample.IO(j).Feature(feat))
 				//}
-				r.mut.RLock()
 				pred = uint32(net.Infer2(sample.IO(j)))
-				r.mut.RUnlock()
 				log.Now().Debugf("Sample IO pred %d %d: %d", i, j, pred)
 			}
+			if vote != nil {
+				vote(i, ai_sentence.Sentence[i].Choices[j][0], pred)
+			}
 			if pred == 1 && !accept {
 				accept = true
 				chosed = ai_sentence.Sentence[i].Choices[j]
@@ -215,11 +197,13 @@ func (r *HashtronHomonymSelectorRepository) Select(isReverse bool, lang string,
 
 func NewHashtronHomonymSelectorRepository(di *DependencyInjection) *HashtronHomonymSelectorRepository {
 	getter := MustAny[interfaces.DictGetter](di)
-	hlangs := make(hlanguages)
+	models := snapshot.New[homonymModel]()
+	forgetOnReload(di, models.Forget)
+
 	return &HashtronHomonymSelectorRepository{
 		getter: &getter,
-		hlang:  &hlangs,
-		mut:    &sync.RWMutex{},
+		models: models,
+		res:    residencyOf(di),
 	}
 }
 
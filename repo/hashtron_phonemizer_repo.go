@@ -1,22 +1,23 @@
 package repo
 
 import (
-	"bytes"
 	"encoding/json"
 	"github.com/neurlang/classifier/datasets/phonemizer_ulevel"
 	"github.com/neurlang/classifier/hashtron"
 	//"github.com/neurlang/classifier/layer/full"
 	"github.com/neurlang/classifier/hash"
-	"github.com/neurlang/classifier/layer/crossattention"
 	//"github.com/neurlang/classifier/layer/majpool2d"
 	//"github.com/neurlang/classifier/layer/parity"
-	"github.com/neurlang/classifier/layer/sochastic"
-	"github.com/neurlang/classifier/layer/sum"
 	"github.com/neurlang/classifier/net/feedforward"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/manifest"
+	"github.com/neurlang/goruut/pkg/phonealign"
+	"github.com/neurlang/goruut/pkg/segment"
+	"github.com/neurlang/goruut/pkg/snapshot"
 	"github.com/neurlang/goruut/repo/interfaces"
+	"github.com/neurlang/goruut/repo/models"
+	"golang.org/x/text/unicode/norm"
 	"strings"
-	"sync"
 	"unicode"
 )
 
@@ -24,23 +25,36 @@ import (
 import . "github.com/martinarisk/di/dependency_injection"
 
 type IHashtronPhonemizerRepository interface {
-	CleanWord(isReverse bool, word string, languages []string) (ret string, lpunct string, rpunct string)
+	CleanWord(isReverse bool, word string, languages []string) (ret string, lpunct string, rpunct string, status models.CleanStatus)
 	CheckWord(isReverse bool, lang, word, ipa string) bool
 	PhonemizeWords(isReverse bool, lang string, word string) []map[string]uint32
 	ExplainWord(isReverse bool, word1, word2, lang string) (ret map[string][]string)
+	AlignWord(isReverse bool, lang, word, ipa string) [][2]string
+	TraceWord(isReverse bool, lang string, word string) *models.DecodeTrace
+	ModelVersion(isReverse bool, lang string) string
 	//PhonemizeWord(isReverse bool, lang string, word string) map[uint64]string
 }
 type HashtronPhonemizerRepository struct {
 	getter *interfaces.DictGetter
 
-	mut    *sync.RWMutex
-	lang   *languages
-	phoner *interfaces.Phonemizer
-	nets   *map[string]*feedforward.FeedforwardNetwork
+	models *snapshot.Map[phonemizerModel]
+	res    tracker
 
 	aregnets *map[string]*feedforward.FeedforwardNetwork
 }
 
+// phonemizerModel is a loaded language in one direction, immutable once published. The
+// net is nil when the language has no model.
+type phonemizerModel struct {
+	lang *language
+	net  *feedforward.FeedforwardNetwork
+	arch manifest.Architecture
+	// size is the compressed size of the weights, the approximate memory of the net.
+	size int64
+	// version is the checksum of the weights.
+	version string
+}
+
 func hashtronHash(str string) uint32 {
 	return hash.StringHash(0, str)
 }
@@ -63,15 +77,14 @@ type language struct {
 	DstMultiSuffix []string            `json:"DstMultiSuffix"`
 	DropLast       []string            `json:"DropLast"`
 	SrcDuplicate   [][]string          `json:"SrcDuplicate"`
+	// Transliterate maps letters foreign to the language, which have no known base letter, to known letters.
+	Transliterate map[string]string `json:"Transliterate"`
+	// Punctuation lists the punctuation marks of the language, these are never letters.
+	Punctuation map[string]struct{} `json:"Punctuation"`
 	//Histogram         []string            `json:"Histogram"`
-	mapSrcMultiLen    int
-	mapSrcMultiSufLen int
-	mapSrcMulti       map[string]struct{}
-	mapDstMulti       map[string]struct{}
-	mapSrcMultiSuffix map[string]struct{}
-	mapDstMultiSuffix map[string]struct{}
-	mapLetters        map[string]struct{}
-	mapDropLast       map[string]struct{}
+	mapLetters  map[string]struct{}
+	mapDropLast map[string]struct{}
+	segmenter   *segment.Segmenter
 }
 
 func mapize(arr []string) (out map[string]struct{}) {
@@ -83,11 +96,8 @@ func mapize(arr []string) (out map[string]struct{}) {
 }
 
 func (l *language) mapize() {
-	l.mapSrcMulti = mapize(l.SrcMulti)
-	l.mapDstMulti = mapize(l.DstMulti)
-	l.mapSrcMultiSuffix = mapize(l.SrcMultiSuffix)
-	l.mapDstMultiSuffix = mapize(l.DstMultiSuffix)
 	l.mapDropLast = mapize(l.DropLast)
+	l.segmenter = segment.New(segment.Multi(l.Mapping, l.SrcMulti), mapize(l.SrcMultiSuffix))
 	l.SrcMulti = nil
 	l.DstMulti = nil
 	l.SrcMultiSuffix = nil
@@ -95,31 +105,6 @@ func (l *language) mapize() {
 	l.DropLast = nil
 }
 
-func (l *language) srcdst() {
-	for k, v := range l.Mapping {
-		if v == nil || len(v) == 0 {
-			continue
-		}
-		if len([]rune(k)) > 1 {
-			l.mapSrcMulti[k] = struct{}{}
-		}
-		for _, w := range v {
-			if len([]rune(w)) > 1 {
-				l.mapDstMulti[w] = struct{}{}
-			}
-		}
-	}
-	for k := range l.mapSrcMulti {
-		if len([]rune(k)) > l.mapSrcMultiLen {
-			l.mapSrcMultiLen = len([]rune(k))
-		}
-	}
-	for k := range l.mapSrcMultiSuffix {
-		if len([]rune(k)) > l.mapSrcMultiSufLen {
-			l.mapSrcMultiSufLen = len([]rune(k))
-		}
-	}
-}
 func (l *language) letters() {
 	l.mapLetters = make(map[string]struct{})
 	for k := range l.Mapping {
@@ -130,184 +115,96 @@ func (l *language) letters() {
 			addLetters(v, l.mapLetters)
 		}
 	}
+	for mark := range l.Punctuation {
+		delete(l.mapLetters, mark)
+	}
 }
 
 /*
-	func (l *languages) Histogram(isReverse bool, lang string) []string {
-		var reverse string
-		if isReverse {
-			reverse = "_reverse"
-		}
-		if (*l)[lang+reverse] == nil {
+	func (l *language) histogram() []string {
+		if l == nil {
 			return nil
 		}
-		return (*l)[lang+reverse].Histogram
+		return l.Histogram
 	}
 */
-func (l *languages) SrcMulti(isReverse bool, lang string) map[string]struct{} {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	return (*l)[lang+reverse].mapSrcMulti
-}
-func (l *languages) SrcDuplicate(isReverse bool, lang string) [][]string {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	return (*l)[lang+reverse].SrcDuplicate
-}
-
-func (l *languages) DstMulti(isReverse bool, lang string) map[string]struct{} {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	return (*l)[lang+reverse].mapDstMulti
-}
-func (l *languages) SrcMultiSuffix(isReverse bool, lang string) map[string]struct{} {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	return (*l)[lang+reverse].mapSrcMultiSuffix
-}
-func (l *languages) DstMultiSuffix(isReverse bool, lang string) map[string]struct{} {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	return (*l)[lang+reverse].mapDstMultiSuffix
-}
-func (l *languages) Map(isReverse bool, lang string) map[string]map[string]struct{} {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	if (*l)[lang+reverse] == nil {
+func (l *language) duplicates() [][]string {
+	if l == nil {
 		return nil
 	}
-	return nil
+	return l.SrcDuplicate
 }
-func (l *languages) DroppedLast(isReverse bool, lang, last string) bool {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	if (*l)[lang+reverse] == nil {
+func (l *language) droppedLast(last string) bool {
+	if l == nil {
 		return false
 	}
-	_, ok := (*l)[lang+reverse].mapDropLast[last]
+	_, ok := l.mapDropLast[last]
 	return ok
 }
-func (l *languages) Slice(isReverse bool, lang string) map[string][]string {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	if (*l)[lang+reverse] == nil {
+func (l *language) slice() map[string][]string {
+	if l == nil {
 		return nil
 	}
-	return (*l)[lang+reverse].Mapping
+	return l.Mapping
 }
-func (l *languages) IsLetter(isReverse bool, lang, run string) bool {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
-	}
-	if (*l)[lang+reverse] == nil {
+func (l *language) isLetter(run string) bool {
+	if l == nil {
 		return false
 	}
-	_, ok := (*l)[lang+reverse].mapLetters[run]
+	_, ok := l.mapLetters[run]
 	return ok
 }
 
-func (l *languages) SrcSlice(isReverse bool, language string, word []rune) (o []string) {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
+// transliterate returns the known letters replacing a letter foreign to the language.
+func (l *language) transliterate(run string) (string, bool) {
+	if l == nil {
+		return "", false
 	}
-	lang := (*l)[language+reverse]
-outer:
-	for i := 0; i < len(word); i++ {
-		if lang != nil {
-			for j := lang.mapSrcMultiLen; j > 0; j-- {
-				for multi := range lang.mapSrcMulti {
-					if len(multi) != j {
-						continue
-					}
-					if strings.HasPrefix(string(word[i:]), multi) {
-						o = append(o, multi)
-						i += len([]rune(multi)) - 1
-						if i >= len(word) {
-							return
-						}
-						continue outer
-					}
-				}
-			}
-			for j := lang.mapSrcMultiSufLen; j > 0; j-- {
-				for multi := range lang.mapSrcMultiSuffix {
-					if len(multi) != j {
-						continue
-					}
-					if len(o) > 0 && strings.HasPrefix(string(word[i:]), multi) {
-						o[len(o)-1] += multi
-						i += len([]rune(multi)) - 1
-						if i >= len(word) {
-							return
-						}
-						continue outer
-					}
-				}
-			}
-		}
-		o = append(o, string(word[i]))
+	ret, ok := l.Transliterate[run]
+	return ret, ok
+}
+
+// srcSlice splits the word into the units of the language by longest match.
+func (l *language) srcSlice(word []rune) []string {
+	var segmenter *segment.Segmenter
+	if l != nil {
+		segmenter = l.segmenter
 	}
-	return o
+	return segmenter.Slice(word)
 }
 
-func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string) {
+// LoadLanguage returns the language, loading it the first time, nil when it cannot be loaded.
+func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string) *phonemizerModel {
 	var reverse string
 	if isReverse {
 		reverse = "_reverse"
 	}
-
-	// First check with read lock to avoid unnecessary write lock
-	r.mut.RLock()
-	if r.nets != nil && (*r.nets)[lang+reverse] != nil {
-		log.Now().Debugf("Language %s already loaded", lang)
-		r.mut.RUnlock()
-		return
+	model, loaded := r.models.Load(lang+reverse, func() *phonemizerModel {
+		return r.loadModel(reverse, lang)
+	})
+	if loaded && model.net != nil {
+		r.res.Loaded(lang, model.size)
 	}
-	r.mut.RUnlock()
-
-	// Double-checked locking: acquire write lock and check again
-	r.mut.Lock()
-	defer r.mut.Unlock()
+	return model
+}
 
-	nets := r.nets
-	if nets == nil {
-		netss := make(map[string]*feedforward.FeedforwardNetwork)
-		nets = &netss
-		r.nets = &netss
-		log.Now().Debugf("Language %s made map of nets", lang)
+// ModelVersion returns the checksum of the weights of the language, empty when it has no model.
+func (r *HashtronPhonemizerRepository) ModelVersion(isReverse bool, lang string) string {
+	if model := r.LoadLanguage(isReverse, lang); model != nil {
+		return model.version
 	}
-	/*
-		aregnets := r.aregnets
-		if aregnets == nil {
-			aregnetss := make(map[string]*feedforward.FeedforwardNetwork)
-			aregnets = &aregnetss
-			r.aregnets = &aregnetss
-			log.Now().Debugf("Language %s made map of nets", lang)
-		}
-	*/
-	if (*nets)[lang+reverse] != nil {
-		log.Now().Debugf("Language %s already loaded", lang)
-		return
+	return ""
+}
+
+// languageOf returns the language, loading it the first time, nil when it cannot be loaded.
+func (r *HashtronPhonemizerRepository) languageOf(isReverse bool, lang string) *language {
+	if model := r.LoadLanguage(isReverse, lang); model != nil {
+		return model.lang
 	}
+	return nil
+}
 
+func (r *HashtronPhonemizerRepository) loadModel(reverse, lang string) (model *phonemizerModel) {
 	var language_files = []string{"language" + reverse + ".json"}
 	for _, file := range language_files {
 		log.Now().Debugf("Language %s loading file", file)
@@ -322,15 +219,14 @@ func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string)
 		}
 
 		langone.mapize()
-		langone.srcdst()
 		langone.letters()
 
 		log.Now().Debugf("Language %s loaded: %v", lang, langone)
 
-		(*r.lang)[lang+reverse] = &langone
-
-		iface := (interfaces.Phonemizer)(&(*r.lang))
-		r.phoner = &iface
+		model = &phonemizerModel{lang: &langone}
+	}
+	if model == nil {
+		return nil
 	}
 
 	var files = []string{
@@ -338,7 +234,7 @@ func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string)
 		"weights6" + reverse + ".json.zlib",
 	}
 
-	for i, file := range files {
+	for _, file := range files {
 		compressedData := log.Error1((*r.getter).GetDict(lang, file))
 
 		if compressedData == nil {
@@ -346,37 +242,18 @@ func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string)
 		}
 
 		if (*r.getter).IsNewFormat(compressedData) {
-			bytesReader := bytes.NewReader(compressedData)
-
-			switch i {
-			case 0:
-				const fanout1 = 24
-				const fanout2 = 1
-				const fanout3 = 4
-				const fanout4 = 32
-
-				var net feedforward.FeedforwardNetwork
-				net.NewLayer(fanout1*fanout2, 0)
-				for i := 0; i < fanout3; i++ {
-					net.NewCombiner(crossattention.MustNew3(fanout1, fanout2))
-					net.NewLayerPI(fanout1*fanout2, 0, 0)
-					net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4-8*byte(i), uint32(i)))
-					net.NewLayerPI(fanout1*fanout2, 0, 0)
-				}
-				net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4, fanout3))
-				net.NewLayer(fanout1*fanout2, 0)
-				net.NewCombiner(sum.MustNew([]uint{fanout1 * fanout2}, 0))
-				net.NewLayer(1, 0)
-
-				(*r.nets)[lang+reverse] = &net
+			net, arch, err := loadModel(*r.getter, lang, file, compressedData)
+			if err != nil {
+				log.Now().Errorf("Language %s model %s: %v", lang, file, err)
+				return
 			}
-			err := (*r.nets)[lang+reverse].ReadZlibWeights(bytesReader)
-			log.Error0(err)
+			model.net, model.arch, model.size = net, arch, int64(len(compressedData))
+			model.version = manifest.Sum(compressedData)
 
 			return
 		} /*else if !isReverse  doesnt work: && (*r.getter).IsOldFormat(compressedData) {
 			bytesReader := bytes.NewReader(compressedData)
-			err := (*r.nets)[lang+reverse].ReadCompressedWeights(bytesReader)
+			err := model.net.ReadCompressedWeights(bytesReader)
 			log.Error0(err)
 			return
 		}*/
@@ -410,6 +287,7 @@ func (r *HashtronPhonemizerRepository) LoadLanguage(isReverse bool, lang string)
 	   		}
 	   	}
 	*/
+	return
 }
 
 func isCombining(r uint32) bool {
@@ -451,10 +329,46 @@ func addLetters(word string, mapping map[string]struct{}) {
 	}
 }
 
-// CleanWord returns cleaned word, left punct, right punct
-func (r *HashtronPhonemizerRepository) CleanWord(isReverse bool, word string, languages []string) (ret string, lpunct string, rpunct string) {
+// isLetter reports whether any of the languages knows the letter.
+func isLetter(run string, languages []*language) bool {
 	for _, lang := range languages {
-		r.LoadLanguage(isReverse, lang)
+		if lang.isLetter(run) {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignLetter replaces a letter none of the languages knows: by the letter stripped of
+// its trailing combining marks one by one, as long as a language knows the result, else
+// by the transliteration of the first language which has one. It returns false when
+// neither works.
+func foreignLetter(run string, languages []*language) (string, bool) {
+	decomposed := []rune(norm.NFD.String(run))
+	for n := len(decomposed) - 1; n > 0 && isCombining(uint32(decomposed[n])); n-- {
+		base := norm.NFC.String(string(decomposed[:n]))
+		if isLetter(base, languages) {
+			return base, true
+		}
+	}
+	for _, lang := range languages {
+		for _, form := range []string{run, norm.NFC.String(run)} {
+			if ret, ok := lang.transliterate(form); ok {
+				return ret, true
+			}
+		}
+	}
+	return "", false
+}
+
+// CleanWord returns cleaned word, left punct, right punct. Letters the languages do not know
+// are replaced by known letters if possible, else dropped, the status tells which happened.
+func (r *HashtronPhonemizerRepository) CleanWord(isReverse bool, word string, languages []string) (ret string, lpunct string, rpunct string, status models.CleanStatus) {
+	var langs = make([]*language, 0, len(languages))
+	for _, lang := range languages {
+		if model := r.LoadLanguage(isReverse, lang); model != nil {
+			langs = append(langs, model.lang)
+		}
 	}
 
 	reverse := make([]uint32, len([]rune(word)))
@@ -482,16 +396,24 @@ func (r *HashtronPhonemizerRepository) CleanWord(isReverse bool, word string, la
 	log.Now().Debugf("strings: %v, len: %v", strings, len(strings))
 	for i, run := range strings {
 
-		var isLanguageLetter = false
-		r.mut.RLock()
-		for _, lang := range languages {
+		var isLanguageLetter = isLetter(run, langs)
+		var isForeignLetter = !isLanguageLetter && unicode.IsLetter([]rune(run)[0])
+		var replaced string
+		if isForeignLetter {
+			replaced, isLanguageLetter = foreignLetter(run, langs)
+		}
+
+		if isForeignLetter {
 			if isLanguageLetter {
-				break
+				log.Now().Debugf("Foreign letter %s replaced: %s", run, replaced)
+				status.Altered = true
+				ret += replaced
+			} else {
+				log.Now().Debugf("Foreign letter dropped: %s", run)
+				status.ForeignLetters = true
 			}
-			isLanguageLetter = r.lang.IsLetter(isReverse, lang, run)
+			continue
 		}
-		r.mut.RUnlock()
-
 		if !isLanguageLetter {
 			if 2*i < len(strings) {
 				lpunct += run
@@ -509,26 +431,18 @@ func (r *HashtronPhonemizerRepository) CleanWord(isReverse bool, word string, la
 }
 
 func (r *HashtronPhonemizerRepository) CheckWord(isReverse bool, lang, word, ipa string) bool {
-	r.LoadLanguage(isReverse, lang)
-
-	r.mut.RLock()
-	mapLangIsNil := r.lang.Slice(isReverse, lang) == nil
-	r.mut.RUnlock()
-	if mapLangIsNil {
+	l := r.languageOf(isReverse, lang)
+	if l.slice() == nil {
 		return false
 	}
-	r.mut.RLock()
-	srca := r.lang.SrcSlice(isReverse, lang, []rune(word))
-	r.mut.RUnlock()
+	srca := l.srcSlice([]rune(word))
 	if len(srca) == 0 {
 		return false
 	}
 outer:
 	for i := 0; i < len(srca); i++ {
 		srcv := srca[i]
-		r.mut.RLock()
-		m := r.lang.Slice(isReverse, lang)[string(srcv)]
-		r.mut.RUnlock()
+		m := l.slice()[string(srcv)]
 		if len(m) == 0 {
 			return false
 		}
@@ -621,38 +535,151 @@ func copystrings(s []string) (r []string) {
 
 func (r *HashtronPhonemizerRepository) ExplainWord(isReverse bool, word1, word2, lang string) (ret map[string][]string) {
 	ret = make(map[string][]string)
-	r.LoadLanguage(isReverse, lang)
-	r.mut.RLock()
-	srca := r.lang.SrcSlice(isReverse, lang, []rune(word1))
-	r.mut.RUnlock()
+	l := r.languageOf(isReverse, lang)
+	srca := l.srcSlice([]rune(word1))
 	for i := 0; i < len(srca); i++ {
 		srcv := srca[i]
-		r.mut.RLock()
-		m := copystrings(r.lang.Slice(isReverse, lang)[string(srcv)])
-		r.mut.RUnlock()
+		m := copystrings(l.slice()[string(srcv)])
 		ret[srcv] = m
 	}
 	return
 }
 
-func (r *HashtronPhonemizerRepository) PhonemizeWords(isReverse bool, lang string, word string) (ret []map[string]uint32) {
-	var reverse string
-	if isReverse {
-		reverse = "_reverse"
+// AlignWord returns the (source chunk, destination chunk) pairs of a word and its pronunciation.
+// The word is segmented the way PhonemizeWords does it, and the segments are aligned against
+// the options of the language Map, trying options in Map order. When the Map cannot explain
+// the pair, the segments are aligned with the phones by a weighted edit distance instead.
+func (r *HashtronPhonemizerRepository) AlignWord(isReverse bool, lang, word, ipa string) (ret [][2]string) {
+	l := r.languageOf(isReverse, lang)
+	ipa = strings.ReplaceAll(ipa, "_", "")
+	if word == "" {
+		return nil
+	}
+
+	mapping := l.slice()
+	srcSame := l.duplicates()
+	if mapping == nil {
+		return [][2]string{{word, ipa}}
+	}
+
+	var replaced = word
+	for _, rule := range srcSame {
+		for j := 1; j < len(rule); j++ {
+			replaced = strings.ReplaceAll(replaced, rule[j], rule[0])
+		}
+	}
+	srca := l.srcSlice([]rune(replaced))
+	droppedLast := len(srca) > 0 && l.droppedLast(srca[len(srca)-1])
+
+	// show the original graphemes when the duplicate rules kept the length
+	var chunks = srca
+	if runes := []rune(word); replaced != word && len(runes) == len([]rune(replaced)) {
+		chunks = make([]string, len(srca))
+		var pos int
+		for i, v := range srca {
+			n := len([]rune(v))
+			chunks[i] = string(runes[pos : pos+n])
+			pos += n
+		}
 	}
-	r.LoadLanguage(isReverse, lang)
 
-	r.mut.RLock()
-	mapLangIsNil := r.lang.Slice(isReverse, lang) == nil
-	r.mut.RUnlock()
-	if mapLangIsNil {
+	options := func(i int) (ret []string) {
+		m := mapping[srca[i]]
+		if i == len(srca)-1 && droppedLast {
+			ret = append(ret, "")
+		}
+		for _, option := range m {
+			ret = append(ret, strings.ReplaceAll(option, "_", ""))
+		}
+		if len(ret) == 0 {
+			ret = append(ret, "")
+		}
+		return
+	}
+
+	// backtracking over the Map options, failed states are memoized
+	var failed = make(map[[2]int]bool)
+	var dsta = make([]string, len(srca))
+	var solve func(i, j int) bool
+	solve = func(i, j int) bool {
+		if i == len(srca) {
+			return j == len(ipa)
+		}
+		if failed[[2]int{i, j}] {
+			return false
+		}
+		for _, option := range options(i) {
+			if strings.HasPrefix(ipa[j:], option) && solve(i+1, j+len(option)) {
+				dsta[i] = option
+				return true
+			}
+		}
+		failed[[2]int{i, j}] = true
+		return false
+	}
+	if solve(0, 0) {
+		for i := range srca {
+			ret = append(ret, [2]string{chunks[i], dsta[i]})
+		}
+		return
+	}
+
+	log.Now().Debugf("Map cannot explain %s as %s, aligning by edit distance", word, ipa)
+	phones := phonealign.Phones(ipa)
+	var segments = make([]int, len(srca))
+	for i := range segments {
+		segments[i] = i
+	}
+	pairs, _ := phonealign.Align(segments, phones, phonealign.Costs[int, string]{
+		Subst: func(i int, phone string) float64 {
+			for _, option := range options(i) {
+				if option == phone {
+					return 0
+				}
+			}
+			for _, option := range options(i) {
+				if option != "" && strings.Contains(option, phone) {
+					return 0.5
+				}
+			}
+			return 1
+		},
+	})
+	var last int
+	for _, pair := range pairs {
+		if pair.A >= 0 {
+			last = pair.A
+		}
+		if pair.B >= 0 {
+			dsta[last] += phones[pair.B]
+		}
+	}
+	for i := range srca {
+		ret = append(ret, [2]string{chunks[i], dsta[i]})
+	}
+	return
+}
+
+func (r *HashtronPhonemizerRepository) PhonemizeWords(isReverse bool, lang string, word string) (ret []map[string]uint32) {
+	return r.phonemizeWords(isReverse, lang, word, nil)
+}
+
+// TraceWord phonemizes the word like PhonemizeWords and records every decision of the model.
+func (r *HashtronPhonemizerRepository) TraceWord(isReverse bool, lang string, word string) (trace *models.DecodeTrace) {
+	trace = &models.DecodeTrace{Word: word}
+	r.phonemizeWords(isReverse, lang, word, trace)
+	return
+}
+
+func (r *HashtronPhonemizerRepository) phonemizeWords(isReverse bool, lang string, word string, trace *models.DecodeTrace) (ret []map[string]uint32) {
+	model := r.LoadLanguage(isReverse, lang)
+	if model == nil || model.lang.slice() == nil {
 		return []map[string]uint32{}
 	}
+	l, net, arch := model.lang, model.net, model.arch
 
 	var backoffs = 10
-	r.mut.RLock()
-	srcSame := r.lang.SrcDuplicate(isReverse, lang)
-	r.mut.RUnlock()
+	srcSame := l.duplicates()
 
 	for _, rule := range srcSame {
 		for j := 1; j < len(rule); j++ {
@@ -660,29 +687,38 @@ func (r *HashtronPhonemizerRepository) PhonemizeWords(isReverse bool, lang strin
 		}
 	}
 
-	r.mut.RLock()
-	srca := r.lang.SrcSlice(isReverse, lang, []rune(word))
-	r.mut.RUnlock()
+	srca := l.srcSlice([]rune(word))
 	dsta := []string{}
+	if trace != nil {
+		trace.Segments = srca
+	}
+	var step *models.DecodeStep
+	record := func(chosen string) {
+		if trace != nil {
+			step.Chosen = chosen
+			trace.Steps = append(trace.Steps, *step)
+		}
+	}
 
 	var lastspace = 0
 outer:
 	for i := 0; i < len(srca); i++ {
 		srcv := srca[i]
-		r.mut.RLock()
-		m := r.lang.Slice(isReverse, lang)[string(srcv)]
+		m := l.slice()[string(srcv)]
 		if i == len(srca)-1 {
-			if r.lang.DroppedLast(isReverse, lang, string(srcv)) {
+			if l.droppedLast(string(srcv)) {
 				m = append([]string{""}, m...)
 			}
 		}
-		r.mut.RUnlock()
+		step = &models.DecodeStep{Index: i, Segment: srcv}
 
 		if len(m) == 0 {
 			dsta = append(dsta, "")
+			record("")
 			continue
 		}
 		if len(m) == 1 {
+			step.Single = true
 			for _, mfirst := range m {
 				if mfirst == "_" {
 					lastspace = i + 1
@@ -692,18 +728,17 @@ outer:
 					lastspace = i + 1
 				}
 				dsta = append(dsta, mfirst)
+				record(mfirst)
 				break
 			}
 			continue
 		}
 		for _, option := range m {
+			var traced = models.DecodeOption{Option: option}
 			srcaR := srca[lastspace:]
 			dstaR := dsta[lastspace:]
 			origi := i
 			i := i - lastspace
-			r.mut.RLock()
-			net := (*r.nets)[lang+reverse]
-			r.mut.RUnlock()
 			var multiword = lastspace > 0
 			if net == nil {
 				log.Now().Errorf("Net is nil")
@@ -711,18 +746,18 @@ outer:
 			}
 			var predicted int
 			for q := 0; (!multiword && q == 0) || (multiword && q < len(srcaR)-i); q++ {
-				const fanout1new = 24
-				var input2 = phonemizer_ulevel.NewInferenceSubsample(srcaR, dstaR, option, fanout1new/3)
+				var input2 = phonemizer_ulevel.NewInferenceSubsample(srcaR, dstaR, option, arch.Fanout1/3)
 				var pred int
-				r.mut.RLock()
 				if true { // newest model
 					pred = int(net.Infer2(input2))
 				}
-				r.mut.RUnlock()
 				predicted += pred
+				traced.Votes = append(traced.Votes, pred)
 				log.Now().Debugf("Model predicted: %v %v %v -> %d", srcaR, dstaR, option, pred)
 			}
-			if (!multiword && predicted == 1) || (multiword && 2*predicted > len(srcaR)) {
+			traced.Accepted = (!multiword && predicted == 1) || (multiword && 2*predicted > len(srcaR))
+			step.Options = append(step.Options, traced)
+			if traced.Accepted {
 				if option == "_" {
 					lastspace = origi + 1
 				} else if strings.HasPrefix(option, "_") {
@@ -731,15 +766,20 @@ outer:
 					lastspace = origi + 1
 				}
 				dsta = append(dsta, option)
+				record(option)
 				continue outer
 			}
 		}
 		if backoffs > 0 {
+			step.Backoff = true
+			step.RestartAt = lastspace
+			record("")
 			i = lastspace - 1
 			dsta = dsta[:lastspace]
 			backoffs--
 			continue
 		}
+		step.Fallback = true
 		for _, mfirst := range m {
 			if mfirst == "_" {
 				lastspace = i + 1
@@ -749,9 +789,14 @@ outer:
 				lastspace = i + 1
 			}
 			dsta = append(dsta, mfirst)
+			record(mfirst)
 			break
 		}
 	}
+	if trace != nil {
+		trace.Output = dsta
+		trace.Phonetic = strings.Trim(strings.Join(dsta, ""), "_")
+	}
 	var src, dst string
 
 	push := func() {
@@ -783,12 +828,13 @@ outer:
 
 func NewHashtronPhonemizerRepository(di *DependencyInjection) *HashtronPhonemizerRepository {
 	getter := MustAny[interfaces.DictGetter](di)
-	langs := make(languages)
+	models := snapshot.New[phonemizerModel]()
+	forgetOnReload(di, models.Forget)
 
 	return &HashtronPhonemizerRepository{
 		getter: &getter,
-		lang:   &langs,
-		mut:    &sync.RWMutex{},
+		models: models,
+		res:    residencyOf(di),
 	}
 }
 
@@ -1,5 +1,18 @@
 package interfaces
 
+import "github.com/neurlang/goruut/pkg/ipaflavor"
+
 type IpaFlavor interface {
 	GetIpaFlavors() map[string]map[string]string
 }
+
+// IpaFlavorRules is optional, it adds contextual rules to the ipa flavors
+type IpaFlavorRules interface {
+	GetIpaFlavorRules() map[string][]ipaflavor.Rule
+	GetIpaPhoneClasses() map[string][]string
+}
+
+// DefaultIpaFlavors is optional, it supplies flavors for requests which name none
+type DefaultIpaFlavors interface {
+	GetDefaultIpaFlavors() map[string][]string
+}
@@ -1,5 +1,7 @@
 package interfaces
 
+import "time"
+
 type ModelStorage interface {
 	GetDict(lang, filename string) ([]byte, error)
 	HaveLang(lang string) bool
@@ -12,3 +14,13 @@ type LoadModels interface {
 		Size int64
 	}
 }
+
+// WatchModels is optional, it sets how often the loaded models are checked for changes, zero disables it
+type WatchModels interface {
+	GetWatchModels() time.Duration
+}
+
+// ReloadNotifier is optional, it calls the listeners with the language whose files changed
+type ReloadNotifier interface {
+	OnReload(listener func(lang string))
+}
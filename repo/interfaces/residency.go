@@ -0,0 +1,22 @@
+package interfaces
+
+import "github.com/neurlang/goruut/pkg/residency"
+
+// MemoryBudget is optional, it bounds the approximate bytes of the loaded languages, zero is unbounded
+type MemoryBudget interface {
+	GetMemoryBudget() int64
+	GetPinnedLanguages() []string
+}
+
+// Residency is optional, it unloads the least recently used languages to keep within the memory budget
+type Residency interface {
+	// Use marks the language used, it may unload other languages.
+	Use(lang string)
+	// Loaded adds the approximate bytes a repository holds for the language.
+	Loaded(lang string, bytes int64)
+	// Forget drops the bytes of a language the repositories forgot themselves.
+	Forget(lang string)
+	// OnEvict adds a listener called with the language to unload.
+	OnEvict(listener func(lang string))
+	Report() residency.Report
+}
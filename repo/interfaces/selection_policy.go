@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/neurlang/goruut/pkg/ranking"
+
+// SelectionPolicy is optional, it supplies the ranking of pronunciation candidates
+type SelectionPolicy interface {
+	GetSelectionPolicy() *ranking.Policy
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "github.com/neurlang/goruut/pkg/symboltable"
+
+// SymbolTables is optional, it supplies the voice model symbol tables known at startup
+type SymbolTables interface {
+	GetSymbolTables() map[string]*symboltable.Table
+}
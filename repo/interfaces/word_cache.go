@@ -0,0 +1,14 @@
+package interfaces
+
+import "time"
+
+// WordCache is optional, it sizes the cache of the words phonemized by the model, zero takes the defaults
+type WordCache interface {
+	// GetWordCacheSize returns the capacity in words, below zero disables the cache.
+	GetWordCacheSize() int
+	GetWordCacheTTL() time.Duration
+	// GetWordCacheFile returns the file keeping the words across restarts, empty for none.
+	GetWordCacheFile() string
+	// GetWordCacheFileLimit returns the bound of the file in bytes.
+	GetWordCacheFileLimit() int64
+}
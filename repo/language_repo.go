@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"github.com/neurlang/goruut/pkg/registry"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ILanguageRepository interface {
+	// Check returns the error of reading the language, nil when the language can be used,
+	// and marks the language used.
+	Check(isReverse bool, lang string) error
+	// Languages lists the languages compiled in.
+	Languages() []registry.Language
+}
+type LanguageRepository struct {
+	getter *interfaces.DictGetter
+
+	mut *sync.RWMutex
+	ok  *map[string]struct{}
+	res tracker
+}
+
+func (l *LanguageRepository) Check(isReverse bool, lang string) error {
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+	l.mut.RLock()
+	_, ok := (*l.ok)[lang+reverse]
+	l.mut.RUnlock()
+	if ok {
+		l.res.Use(lang)
+		return nil
+	}
+	// only languages which loaded are remembered, clients can send any string
+	if _, err := (*l.getter).GetDict(lang, "language"+reverse+".json"); err != nil {
+		return err
+	}
+	l.mut.Lock()
+	(*l.ok)[lang+reverse] = struct{}{}
+	l.mut.Unlock()
+	l.res.Use(lang)
+	return nil
+}
+
+func (l *LanguageRepository) Languages() []registry.Language {
+	return registry.All()
+}
+
+func NewLanguageRepository(di *DependencyInjection) *LanguageRepository {
+	getter := MustAny[interfaces.DictGetter](di)
+	ok := make(map[string]struct{})
+	return &LanguageRepository{
+		getter: &getter,
+		mut:    &sync.RWMutex{},
+		ok:     &ok,
+		res:    residencyOf(di),
+	}
+}
+
+var _ ILanguageRepository = &LanguageRepository{}
@@ -1,11 +1,16 @@
 package models
 
 import (
+	"fmt"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/ipaflavor"
+	"github.com/neurlang/goruut/pkg/ranking"
+	"github.com/neurlang/goruut/pkg/symboltable"
 	"github.com/sirupsen/logrus"
 	easy "github.com/t-tomalak/logrus-easy-formatter"
 	"io/ioutil"
 	"os"
+	"time"
 )
 
 // AppConfig represents the configuration for the application.
@@ -26,9 +31,28 @@ type AppConfig struct {
 		File string
 		Size int64
 	}
+	// WatchModels is how often the loaded models are checked for changes, such as "2s".
+	WatchModels string
+	// MemoryBudgetMB bounds the approximate memory of the loaded languages in MiB, zero is unbounded.
+	MemoryBudgetMB int64
+	// PinnedLanguages are never unloaded to keep within the memory budget.
+	PinnedLanguages []string
+	// WordCacheSize is how many words phonemized by the model are cached, zero is 10000, below zero none.
+	WordCacheSize int
+	// WordCacheTTL is how long a cached word is kept, such as "1h".
+	WordCacheTTL string
+	// WordCacheFile keeps the cached words across restarts, empty for none.
+	WordCacheFile string
+	// WordCacheFileMB bounds the word cache file in MiB, zero is 64.
+	WordCacheFileMB int64
 
 	BuiltinDictLanguages []string
 	IpaFlavors           map[string]map[string]string
+	IpaFlavorRules       map[string][]ipaflavor.Rule
+	IpaPhoneClasses      map[string][]string
+	DefaultIpaFlavors    map[string][]string
+	SymbolTables         map[string]*symboltable.Table
+	SelectionPolicy      *ranking.Policy
 	PolicyMaxWords       int
 }
 
@@ -60,6 +84,71 @@ func (c *AppConfig) GetIpaFlavors() map[string]map[string]string {
 	return c.IpaFlavors
 }
 
+// GetIpaFlavorRules returns the contextual ipa flavor rules.
+func (c *AppConfig) GetIpaFlavorRules() map[string][]ipaflavor.Rule {
+	return c.IpaFlavorRules
+}
+
+// GetIpaPhoneClasses returns the phone classes referenced by ipa flavor rules.
+func (c *AppConfig) GetIpaPhoneClasses() map[string][]string {
+	return c.IpaPhoneClasses
+}
+
+// GetDefaultIpaFlavors returns the ipa flavors applied per language when a request names none.
+func (c *AppConfig) GetDefaultIpaFlavors() map[string][]string {
+	return c.DefaultIpaFlavors
+}
+
+// GetSelectionPolicy returns the ranking of pronunciation candidates.
+func (c *AppConfig) GetSelectionPolicy() *ranking.Policy {
+	return c.SelectionPolicy
+}
+
+// GetSymbolTables returns the voice model symbol tables.
+func (c *AppConfig) GetSymbolTables() map[string]*symboltable.Table {
+	return c.SymbolTables
+}
+
+// Validate checks that the ipa flavors compile without conflicts and that symbol tables are usable.
+func (c *AppConfig) Validate() error {
+	_, err := ipaflavor.CompileAll(c.IpaFlavors, c.IpaFlavorRules, c.IpaPhoneClasses)
+	if err != nil {
+		return err
+	}
+	for name, table := range c.SymbolTables {
+		if table == nil {
+			return fmt.Errorf("symbol table %s: %w", name, symboltable.ErrEmptyTable)
+		}
+		if err := table.Validate(); err != nil {
+			return fmt.Errorf("symbol table %s: %w", name, err)
+		}
+	}
+	if c.WatchModels != "" {
+		if _, err := time.ParseDuration(c.WatchModels); err != nil {
+			return fmt.Errorf("watch models: %w", err)
+		}
+	}
+	if c.MemoryBudgetMB < 0 {
+		return fmt.Errorf("memory budget: %d MiB is negative", c.MemoryBudgetMB)
+	}
+	if c.WordCacheFileMB < 0 {
+		return fmt.Errorf("word cache file: %d MiB is negative", c.WordCacheFileMB)
+	}
+	if c.WordCacheTTL != "" {
+		if ttl, err := time.ParseDuration(c.WordCacheTTL); err != nil {
+			return fmt.Errorf("word cache ttl: %w", err)
+		} else if ttl <= 0 {
+			return fmt.Errorf("word cache ttl: %s is not positive", c.WordCacheTTL)
+		}
+	}
+	if c.SelectionPolicy != nil {
+		if err := c.SelectionPolicy.Validate(); err != nil {
+			return fmt.Errorf("selection policy: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetPolicyMaxWords returns the policy max word count.
 func (c *AppConfig) GetPolicyMaxWords() int {
 	return c.PolicyMaxWords
@@ -74,6 +163,43 @@ func (c *AppConfig) GetLoadModels() []*struct {
 	return c.LoadModels
 }
 
+// GetWatchModels returns how often the loaded models are checked for changes, zero when they are not.
+func (c *AppConfig) GetWatchModels() time.Duration {
+	d, _ := time.ParseDuration(c.WatchModels)
+	return d
+}
+
+// GetMemoryBudget returns the bound of the memory of the loaded languages in bytes, zero when unbounded.
+func (c *AppConfig) GetMemoryBudget() int64 {
+	return c.MemoryBudgetMB << 20
+}
+
+// GetPinnedLanguages returns the languages which are never unloaded.
+func (c *AppConfig) GetPinnedLanguages() []string {
+	return c.PinnedLanguages
+}
+
+// GetWordCacheSize returns how many words phonemized by the model are cached.
+func (c *AppConfig) GetWordCacheSize() int {
+	return c.WordCacheSize
+}
+
+// GetWordCacheTTL returns how long a cached word is kept, zero when not set.
+func (c *AppConfig) GetWordCacheTTL() time.Duration {
+	d, _ := time.ParseDuration(c.WordCacheTTL)
+	return d
+}
+
+// GetWordCacheFile returns the file keeping the cached words across restarts.
+func (c *AppConfig) GetWordCacheFile() string {
+	return c.WordCacheFile
+}
+
+// GetWordCacheFileLimit returns the bound of the word cache file in bytes, zero when not set.
+func (c *AppConfig) GetWordCacheFileLimit() int64 {
+	return c.WordCacheFileMB << 20
+}
+
 // ConfigureLogger configures the application's logger.
 func (c *AppConfig) ConfigureLogger() {
 
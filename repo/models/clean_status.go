@@ -0,0 +1,17 @@
+package models
+
+// CleanStatus tells how the letters of a word were changed to letters of the language.
+type CleanStatus struct {
+	// Altered is set when letters unknown to the language were replaced by their base letters or transliterated.
+	Altered bool `json:",omitempty"`
+	// ForeignLetters is set when letters unknown to the language were dropped.
+	ForeignLetters bool `json:",omitempty"`
+}
+
+// Or returns the changes made in either s or o.
+func (s CleanStatus) Or(o CleanStatus) CleanStatus {
+	return CleanStatus{
+		Altered:        s.Altered || o.Altered,
+		ForeignLetters: s.ForeignLetters || o.ForeignLetters,
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+// DecodeTrace records the decisions of the model while phonemizing one word.
+type DecodeTrace struct {
+	Word     string
+	Segments []string
+	Steps    []DecodeStep
+	Output   []string
+	Phonetic string
+}
+
+// DecodeStep is one visit of a source segment, a segment is visited again after a backoff.
+type DecodeStep struct {
+	Index   int
+	Segment string
+	Options []DecodeOption
+	Chosen  string
+
+	// Single is set when the Map offers one option and the model is not asked.
+	Single bool `json:",omitempty"`
+	// Backoff is set when the model accepted no option and decoding restarted at RestartAt.
+	Backoff   bool `json:",omitempty"`
+	RestartAt int  `json:",omitempty"`
+	// Fallback is set when the backoffs were exhausted and the first option m[0] was used.
+	Fallback bool `json:",omitempty"`
+}
+
+// DecodeOption is one Map option in the order tried, with the model vote of every multiword iteration.
+type DecodeOption struct {
+	Option   string
+	Votes    []int
+	Accepted bool
+}
+
+// Divergence pinpoints the first segment where the output differs from the expected pronunciation.
+type Divergence struct {
+	Index    int
+	Segment  string
+	Expected string
+	Got      string
+}
+
+// LexiconEntry is one pronunciation found in the lexicon of a language.
+type LexiconEntry struct {
+	Language string
+	Phonetic string
+	Tags     []string
+}
+
+// HomographVote is the homograph selector vote for one lexicon candidate.
+type HomographVote struct {
+	Phonetic string
+	Vote     int
+	Chosen   bool
+}
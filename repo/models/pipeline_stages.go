@@ -0,0 +1,41 @@
+package models
+
+// PipelineStages lists the phonemization stages to disable, the zero value runs all of them.
+type PipelineStages struct {
+	// PrePhonemization skips the language specific normalization of words.
+	PrePhonemization bool `json:",omitempty"`
+	// Numbers skips spelling out numbers.
+	Numbers bool `json:",omitempty"`
+	// Lexicon skips the lookup in the lexicons of the primary and fallback languages.
+	Lexicon bool `json:",omitempty"`
+	// Clitics skips stripping clitics from words missing from the lexicon.
+	Clitics bool `json:",omitempty"`
+	// Compounds skips splitting words missing from the lexicon into lexicon words.
+	Compounds bool `json:",omitempty"`
+	// Cache neither reads nor stores the model output in the word cache.
+	Cache bool `json:",omitempty"`
+	// Model skips the hashtron inference, words which are not in the lexicon are returned empty.
+	Model bool `json:",omitempty"`
+	// Homographs skips the homograph selector, the selection policy alone picks the pronunciation.
+	Homographs bool `json:",omitempty"`
+	// CrossWord skips the cross-word rules rewriting pronunciations at word boundaries.
+	CrossWord bool `json:",omitempty"`
+	// Flavors skips the IPA flavors.
+	Flavors bool `json:",omitempty"`
+}
+
+// Or returns the stages disabled in either p or o.
+func (p PipelineStages) Or(o PipelineStages) PipelineStages {
+	return PipelineStages{
+		PrePhonemization: p.PrePhonemization || o.PrePhonemization,
+		Numbers:          p.Numbers || o.Numbers,
+		Lexicon:          p.Lexicon || o.Lexicon,
+		Clitics:          p.Clitics || o.Clitics,
+		Compounds:        p.Compounds || o.Compounds,
+		Cache:            p.Cache || o.Cache,
+		Model:            p.Model || o.Model,
+		Homographs:       p.Homographs || o.Homographs,
+		CrossWord:        p.CrossWord || o.CrossWord,
+		Flavors:          p.Flavors || o.Flavors,
+	}
+}
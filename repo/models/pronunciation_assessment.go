@@ -0,0 +1,20 @@
+package models
+
+// AssessedPhone is one aligned pair of an expected and a heard phone. Expected is
+// empty for inserted phones, Heard is empty for deleted phones.
+type AssessedPhone struct {
+	Expected string
+	Heard    string
+	Label    string
+}
+
+// AssessedWord is the assessment of one expected word, or of an inserted word
+// when Index is -1. Expected is the lexicon alternative closest to Heard.
+type AssessedWord struct {
+	Index    int
+	Expected string
+	Heard    string
+	Label    string
+	Score    float64
+	Phones   []AssessedPhone
+}
@@ -0,0 +1,52 @@
+package models
+
+// Punctuation types.
+const (
+	PunctuationComma       = "comma"
+	PunctuationPeriod      = "period"
+	PunctuationQuestion    = "question"
+	PunctuationExclamation = "exclamation"
+	PunctuationDash        = "dash"
+	PunctuationEllipsis    = "ellipsis"
+	PunctuationQuote       = "quote"
+	PunctuationBracket     = "bracket"
+	PunctuationOther       = "other"
+)
+
+// Break strengths, named as in SSML.
+const (
+	BreakNone    = "none"
+	BreakXWeak   = "x-weak"
+	BreakWeak    = "weak"
+	BreakMedium  = "medium"
+	BreakStrong  = "strong"
+	BreakXStrong = "x-strong"
+)
+
+// Punctuation classifies a punctuation mark and suggests the prosodic break at it.
+type Punctuation struct {
+	Type  string
+	Break string
+}
+
+// Valid reports whether the type and the break strength are known.
+func (p *Punctuation) Valid() bool {
+	switch p.Type {
+	case PunctuationComma, PunctuationPeriod, PunctuationQuestion, PunctuationExclamation,
+		PunctuationDash, PunctuationEllipsis, PunctuationQuote, PunctuationBracket, PunctuationOther:
+	default:
+		return false
+	}
+	switch p.Break {
+	case BreakNone, BreakXWeak, BreakWeak, BreakMedium, BreakStrong, BreakXStrong:
+	default:
+		return false
+	}
+	return true
+}
+
+// PunctuationToken is one punctuation mark as written.
+type PunctuationToken struct {
+	Text string
+	Punctuation
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/neurlang/classifier/layer/crossattention"
+	"github.com/neurlang/classifier/layer/sochastic"
+	"github.com/neurlang/classifier/layer/sum"
+	"github.com/neurlang/classifier/net/feedforward"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/manifest"
+	"github.com/neurlang/goruut/repo/interfaces"
+)
+
+// newNetwork builds the network of the architecture, ready to read its weights.
+func newNetwork(arch manifest.Architecture) (*feedforward.FeedforwardNetwork, error) {
+	if err := arch.Validate(); err != nil {
+		return nil, err
+	}
+	var fanout1, fanout2, fanout3, fanout4 = arch.Fanout1, arch.Fanout2, arch.Fanout3, byte(arch.Fanout4)
+
+	var net feedforward.FeedforwardNetwork
+	net.NewLayer(fanout1*fanout2, 0)
+	for i := 0; i < fanout3; i++ {
+		net.NewCombiner(crossattention.MustNew3(fanout1, fanout2))
+		net.NewLayerPI(fanout1*fanout2, 0, 0)
+		net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4-8*byte(i), uint32(i)))
+		net.NewLayerPI(fanout1*fanout2, 0, 0)
+	}
+	net.NewCombiner(sochastic.MustNew(fanout1*fanout2, fanout4, uint32(fanout3)))
+	net.NewLayer(fanout1*fanout2, 0)
+	net.NewCombiner(sum.MustNew([]uint{uint(fanout1 * fanout2)}, 0))
+	net.NewLayer(1, 0)
+	return &net, nil
+}
+
+// loadModel checks the model weights against the manifest of the language and builds the
+// network the manifest describes. A language without a manifest gets the default network.
+func loadModel(getter interfaces.DictGetter, lang, file string, data []byte) (*feedforward.FeedforwardNetwork, manifest.Architecture, error) {
+	var m *manifest.Manifest
+	if manifestData, err := getter.GetDict(lang, manifest.FileName); err == nil && len(manifestData) > 0 {
+		if m, err = manifest.Parse(manifestData); err != nil {
+			return nil, manifest.Default, err
+		}
+		if err := m.Verify(file, data); err != nil {
+			return nil, manifest.Default, fmt.Errorf("%w, update the manifest by cmd/manifest", err)
+		}
+	} else {
+		log.Now().Warningf("Language %s has no manifest, model %s is not verified", lang, file)
+	}
+	arch := m.Architecture(file)
+	net, err := newNetwork(arch)
+	if err != nil {
+		return nil, arch, err
+	}
+	if err := net.ReadZlibWeights(bytes.NewReader(data)); err != nil {
+		return nil, arch, err
+	}
+	return net, arch, nil
+}
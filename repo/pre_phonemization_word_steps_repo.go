@@ -232,6 +232,11 @@ func NewPrePhonWordStepsRepository(di *DependencyInjection) *PrePhonWordStepsRep
 	langs := prephonlanguages{
 		mut: &sync.RWMutex{},
 	}
+	forgetOnReload(di, func(key string) {
+		langs.mut.Lock()
+		defer langs.mut.Unlock()
+		delete(langs.lang, key)
+	})
 
 	return &PrePhonWordStepsRepository{
 		getter: &getter,
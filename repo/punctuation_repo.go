@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"encoding/json"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"github.com/neurlang/goruut/repo/models"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IPunctuationRepository interface {
+	// Punctuation returns the punctuation marks of the language: the defaults overridden
+	// and extended by the language.
+	Punctuation(isReverse bool, lang string) map[string]models.Punctuation
+}
+type PunctuationRepository struct {
+	getter *interfaces.DictGetter
+
+	mut  *sync.RWMutex
+	lang *punctuationlanguages
+}
+
+type punctuationlanguages map[string]*punctuationlanguage
+
+type punctuationlanguage struct {
+	Punctuation map[string]models.Punctuation `json:"Punctuation"`
+}
+
+func punctuationMarks(typ, brk string, marks ...string) (ret map[string]models.Punctuation) {
+	ret = make(map[string]models.Punctuation)
+	for _, mark := range marks {
+		ret[mark] = models.Punctuation{Type: typ, Break: brk}
+	}
+	return
+}
+
+// defaultPunctuation classifies the punctuation shared by most languages.
+var defaultPunctuation = func() map[string]models.Punctuation {
+	var ret = make(map[string]models.Punctuation)
+	for _, marks := range []map[string]models.Punctuation{
+		punctuationMarks(models.PunctuationComma, models.BreakMedium, ",", ";", ":", "،", "؛"),
+		punctuationMarks(models.PunctuationPeriod, models.BreakStrong, "."),
+		punctuationMarks(models.PunctuationQuestion, models.BreakStrong, "?", "¿", "؟"),
+		punctuationMarks(models.PunctuationExclamation, models.BreakStrong, "!", "¡"),
+		punctuationMarks(models.PunctuationDash, models.BreakMedium, "—", "–", "―", "--"),
+		punctuationMarks(models.PunctuationDash, models.BreakWeak, "-"),
+		punctuationMarks(models.PunctuationEllipsis, models.BreakStrong, "…", "..."),
+		punctuationMarks(models.PunctuationQuote, models.BreakXWeak, "\"", "'", "«", "»", "„", "“", "”", "‘", "’", "‚", "‹", "›"),
+		punctuationMarks(models.PunctuationBracket, models.BreakWeak, "(", ")", "[", "]", "{", "}"),
+	} {
+		for mark, punct := range marks {
+			ret[mark] = punct
+		}
+	}
+	return ret
+}()
+
+func (s *PunctuationRepository) Punctuation(isReverse bool, lang string) map[string]models.Punctuation {
+	s.LoadLanguage(isReverse, lang)
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	language := (*s.lang)[lang+reverse]
+	if language == nil {
+		return defaultPunctuation
+	}
+	return language.Punctuation
+}
+
+func (p *PunctuationRepository) LoadLanguage(isReverse bool, lang string) {
+	var reverse string
+	if isReverse {
+		reverse = "_reverse"
+	}
+
+	p.mut.RLock()
+	existing_lang := (*p.lang)[lang+reverse]
+	p.mut.RUnlock()
+
+	if existing_lang != nil {
+		return
+	}
+
+	var language_files = []string{"language" + reverse + ".json"}
+	for _, file := range language_files {
+		log.Now().Debugf("Language %s loading file", file)
+		data := log.Error1((*p.getter).GetDict(lang, file))
+
+		var langone punctuationlanguage
+		err := json.Unmarshal(data, &langone)
+		if err != nil {
+			log.Now().Errorf("Error parsing JSON: %v\n", err)
+			continue
+		}
+		var marks = make(map[string]models.Punctuation)
+		for mark, punct := range defaultPunctuation {
+			marks[mark] = punct
+		}
+		for mark, punct := range langone.Punctuation {
+			if mark == "" || !punct.Valid() {
+				log.Now().Errorf("Language %s: invalid punctuation %q: %v", lang, mark, punct)
+				continue
+			}
+			marks[mark] = punct
+		}
+		langone.Punctuation = marks
+		p.mut.Lock()
+		(*p.lang)[lang+reverse] = &langone
+		p.mut.Unlock()
+	}
+}
+
+func NewPunctuationRepository(di *DependencyInjection) *PunctuationRepository {
+	getter := MustAny[interfaces.DictGetter](di)
+	langs := make(punctuationlanguages)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(langs, key)
+	})
+
+	return &PunctuationRepository{
+		getter: &getter,
+		lang:   &langs,
+		mut:    mut,
+	}
+}
+
+var _ IPunctuationRepository = &PunctuationRepository{}
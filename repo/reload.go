@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"github.com/neurlang/goruut/repo/interfaces"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+// forgetOnReload calls forget with the keys of a language, forward and reverse, when the
+// files of the language change or when the language is unloaded to keep within the
+// memory budget, so that the repository loads the language again.
+func forgetOnReload(di *DependencyInjection, forget func(key string)) {
+	onReload(di, func(lang string) {
+		forget(lang)
+		forget(lang + "_reverse")
+	})
+}
+
+// onReload calls listener with the language when its files change or when it is unloaded
+// to keep within the memory budget.
+func onReload(di *DependencyInjection, listener func(lang string)) {
+	var notifier interfaces.ReloadNotifier
+	if Any(di, &notifier) == nil {
+		notifier.OnReload(listener)
+	}
+	var residency interfaces.Residency
+	if Any(di, &residency) == nil {
+		residency.OnEvict(listener)
+	}
+}
+
+// tracker takes the use of the languages and the approximate bytes a repository holds for them.
+type tracker interface {
+	Use(lang string)
+	Loaded(lang string, bytes int64)
+}
+
+// noResidency stands in when the memory of the languages is not tracked.
+type noResidency struct{}
+
+func (noResidency) Use(string)           {}
+func (noResidency) Loaded(string, int64) {}
+
+// residencyOf returns the tracker of the languages.
+func residencyOf(di *DependencyInjection) tracker {
+	var residency interfaces.Residency
+	if Any(di, &residency) != nil {
+		return noResidency{}
+	}
+	return residency
+}
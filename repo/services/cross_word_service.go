@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/crossword"
+	"github.com/neurlang/goruut/repo"
+	"strings"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ICrossWordService interface {
+	Apply(isReverse bool, lang string, selected [][3]string, punct [][2]string) (fired [][]string)
+}
+
+type CrossWordService struct {
+	repo *repo.ICrossWordRulesRepository
+}
+
+// Apply rewrites the pronunciations of the selected words in place by the cross-word
+// rules of the language and returns the names of the rules fired at every word.
+func (p *CrossWordService) Apply(isReverse bool, lang string, selected [][3]string, punct [][2]string) (fired [][]string) {
+	rules := (*p.repo).CrossWordRules(isReverse, lang)
+	if len(rules) == 0 || len(selected) < 2 {
+		return nil
+	}
+	var words = make([]crossword.Word, len(selected))
+	for i, word := range selected {
+		words[i] = crossword.Word{
+			Word:     strings.TrimRight(word[0], " "),
+			Phonetic: word[1],
+		}
+		if word[2] != "" {
+			words[i].Tags = log.Error1(helpers.ParseJson[[]string]([]byte(word[2])))
+		}
+		if i < len(punct) {
+			words[i].PrePunct, words[i].PostPunct = punct[i][0], punct[i][1]
+		}
+	}
+	crossword.Apply(rules, words)
+	fired = make([][]string, len(words))
+	for i, word := range words {
+		if word.Rules != nil {
+			log.Now().Debugf("Cross-word rules %v: %s -> %s", word.Rules, selected[i][1], word.Phonetic)
+		}
+		selected[i][1] = word.Phonetic
+		fired[i] = word.Rules
+	}
+	return
+}
+
+func NewCrossWordService(di *DependencyInjection) *CrossWordService {
+	repoiface := (repo.ICrossWordRulesRepository)(Ptr(MustNeed(di, repo.NewCrossWordRulesRepository)))
+	return &CrossWordService{
+		repo: &repoiface,
+	}
+}
+
+var _ ICrossWordService = &CrossWordService{}
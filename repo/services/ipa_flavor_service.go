@@ -4,53 +4,88 @@ package services
 import (
 	. "github.com/martinarisk/di/dependency_injection"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/ipaflavor"
 	"github.com/neurlang/goruut/repo/interfaces"
-	"strings"
+	"unicode"
 )
 
 type IIpaFlavorService interface {
 	Apply(lang, word string) (ret string)
+	ApplyParts(lang string, parts []string) []string
+	Resolve(lang string, flavors []string) (ret []string)
 }
 
 type IpaFlavorService struct {
-	mapping *map[string]map[string]string
-	longest *map[string]int
+	flavors *map[string]*ipaflavor.Flavor
 }
 
 func (p *IpaFlavorService) Apply(lang, word string) (ret string) {
+	flavor := (*p.flavors)[lang]
+	if flavor == nil {
+		return word
+	}
+	return flavor.Apply(word)
+}
+
+// ApplyParts flavors the word made of the parts as a whole and splits it back into the parts.
+func (p *IpaFlavorService) ApplyParts(lang string, parts []string) []string {
+	return (*p.flavors)[lang].ApplyParts(parts)
+}
+
+// languageFamily returns the leading word of the language name, for example
+// EnglishAmerican is English and Hebrew3 is Hebrew.
+func languageFamily(lang string) string {
+	for i, r := range lang {
+		if i > 0 && (unicode.IsUpper(r) || unicode.IsDigit(r)) {
+			return lang[:i]
+		}
+	}
+	return lang
+}
 
-	for i := (*p.longest)[lang]; i > 0; i-- {
-		for k, v := range (*p.mapping)[lang] {
-			if len(k) != i {
+// Resolve expands every requested flavor into the chain: flavor, flavor_<Language>,
+// flavor_<LanguageFamily>. Language specific flavors are added only if configured.
+func (p *IpaFlavorService) Resolve(lang string, flavors []string) (ret []string) {
+	var seen = make(map[string]bool)
+	add := func(flavor string) {
+		if !seen[flavor] {
+			seen[flavor] = true
+			ret = append(ret, flavor)
+		}
+	}
+	for _, flavor := range flavors {
+		add(flavor)
+		for _, suffix := range []string{lang, languageFamily(lang)} {
+			if suffix == "" {
 				continue
 			}
-
-			if k == v {
-				log.Now().Errorf("Ipa flavour %s does have identical source and dest string: %s to %s", lang, k, v)
+			if (*p.flavors)[flavor+"_"+suffix] != nil {
+				add(flavor + "_" + suffix)
 			}
-
-			word = strings.ReplaceAll(word, k, v)
 		}
 	}
-	return word
+	return
 }
 
 func NewIpaFlavorService(di *DependencyInjection) *IpaFlavorService {
 
 	mapping := MustAny[interfaces.IpaFlavor](di).GetIpaFlavors()
-	longest := make(map[string]int)
 
-	for lang, dict := range mapping {
-		for k := range dict {
-			if len(k) > longest[lang] {
-				longest[lang] = len(k)
-			}
-		}
+	var rules map[string][]ipaflavor.Rule
+	var classes map[string][]string
+	var contextual interfaces.IpaFlavorRules
+	if Any(di, &contextual) == nil {
+		rules = contextual.GetIpaFlavorRules()
+		classes = contextual.GetIpaPhoneClasses()
+	}
+
+	flavors, err := ipaflavor.CompileAll(mapping, rules, classes)
+	if err != nil {
+		log.Now().Errorf("Ipa flavors not compiled: %v", err)
 	}
 
 	return &IpaFlavorService{
-		mapping: &mapping,
-		longest: &longest,
+		flavors: &flavors,
 	}
 }
 
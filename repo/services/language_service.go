@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/pkg/registry"
+	"github.com/neurlang/goruut/repo"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ILanguageService interface {
+	Check(isReverse bool, lang string, langs []string) error
+	Languages() []registry.Language
+}
+
+type LanguageService struct {
+	repo *repo.ILanguageRepository
+}
+
+// Check returns the error of the first language, of the language and its fallback
+// languages, which cannot be used.
+func (l *LanguageService) Check(isReverse bool, lang string, langs []string) error {
+	for _, one := range append([]string{lang}, langs...) {
+		if err := (*l.repo).Check(isReverse, one); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Languages lists the languages compiled in.
+func (l *LanguageService) Languages() []registry.Language {
+	return (*l.repo).Languages()
+}
+
+func NewLanguageService(di *DependencyInjection) *LanguageService {
+	repoiface := (repo.ILanguageRepository)(Ptr(MustNeed(di, repo.NewLanguageRepository)))
+	return &LanguageService{
+		repo: &repoiface,
+	}
+}
+
+var _ ILanguageService = &LanguageService{}
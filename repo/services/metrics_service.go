@@ -0,0 +1,29 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/pkg/wordcache"
+	"github.com/neurlang/goruut/repo"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IMetricsService interface {
+	WordCache() (memory, disk wordcache.Stats)
+}
+
+type MetricsService struct {
+	cach *repo.IWordCachingRepository
+}
+
+// WordCache returns the counters of the word cache in memory and in its file.
+func (m *MetricsService) WordCache() (memory, disk wordcache.Stats) {
+	return (*m.cach).Stats()
+}
+
+func NewMetricsService(di *DependencyInjection) *MetricsService {
+	cach_repo_iface := (repo.IWordCachingRepository)(Ptr(MustNeed(di, repo.NewWordCachingRepository)))
+	return &MetricsService{
+		cach: &cach_repo_iface,
+	}
+}
+
+var _ IMetricsService = &MetricsService{}
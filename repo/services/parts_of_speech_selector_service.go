@@ -4,81 +4,132 @@ import (
 	"github.com/neurlang/classifier/hash"
 	"github.com/neurlang/goruut/helpers"
 	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/ranking"
 	"github.com/neurlang/goruut/repo"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"github.com/neurlang/goruut/repo/models"
+	"sort"
 	"strings"
 )
 
 import . "github.com/martinarisk/di/dependency_injection"
 
 type IPartsOfSpeechSelectorService interface {
-	Select(isReverse bool, lang string, sentence []map[string]uint32, languages []string) (ret [][3]string)
+	Select(isReverse bool, lang string, sentence []map[string]uint32, languages []string, hints [][]string, disable models.PipelineStages) (ret [][3]string)
+	HomographVotes(isReverse bool, lang string, words map[string]uint32) []models.HomographVote
 }
 
 type PartsOfSpeechSelectorService struct {
 	repo   *repo.IDictPhonemizerRepository
 	repoa  *repo.IAutoTaggerRepository
 	repoai *repo.IHashtronHomonymSelectorRepository
+	policy *ranking.Policy
 }
 
-func next_continue_english(lang string, isReverse bool, my_tags, next_tags map[string]bool) bool {
-	if isReverse {
-		return false
+// candidate is one pronunciation of a word, dict is set when it is in the lexicon of the language.
+type candidate struct {
+	ranking.Candidate
+	key  uint32
+	dict bool
+}
+
+// candidates lists the pronunciations of one word with their tags, ranked by the policy.
+// The lexicon order and frequency come from the first language listing the pronunciation.
+func (p *PartsOfSpeechSelectorService) candidates(isReverse bool, lang string, words map[string]uint32, languages []string, hints []string) (orig string, ret []candidate) {
+	for word, k := range words {
+		if k == 0 {
+			orig = strings.TrimRight(word, " ")
+			break
+		}
 	}
-	if !strings.HasPrefix(lang, "English") {
-		return false
+	for word, k := range words {
+		if k == 0 {
+			continue
+		}
+		var c = candidate{
+			Candidate: ranking.Candidate{Phonetic: word, LexiconOrder: -1},
+			key:       k,
+		}
+		for n, lang := range append([]string{lang}, languages...) {
+			var tags = (*p.repo).LookupTags(isReverse, lang, orig, word)
+			log.Now().Debugf("Orig: %s, Word: %s, WordsTags: %s", orig, word, tags)
+			c.Tags = append(c.Tags, (*p.repoa).TagWord(isReverse, lang, orig, word)...)
+			if tags != "" && tags != "[]" {
+				for _, tag := range log.Error1(helpers.ParseJson[[]string]([]byte(tags))) {
+					c.Tags = append(c.Tags, tag)
+					if n == 0 && tag == "dict" {
+						c.dict = true
+					}
+				}
+			}
+			if c.LexiconOrder < 0 {
+				c.LexiconOrder, c.Frequency = (*p.repo).LookupOrder(isReverse, lang, orig, word)
+			}
+		}
+		c.Hints = ranking.MatchHints(hints, c.Tags)
+		ret = append(ret, c)
 	}
-	if my_tags["thi"] && next_tags["consonant1st"] {
-		return true
+	p.sort(ret)
+	return
+}
+
+func (p *PartsOfSpeechSelectorService) sort(candidates []candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return p.policy.Less(&candidates[i].Candidate, &candidates[j].Candidate)
+	})
+}
+
+// homographInput returns the lexicon candidates of a word as the homograph model input.
+// When the caller hinted the part of speech, only the lexicon candidates matching the
+// most hints are offered.
+func homographInput(orig string, candidates []candidate) (inputmap map[string][2]uint32) {
+	inputmap = make(map[string][2]uint32)
+	inputmap[orig+" "] = [2]uint32{0, 0}
+	var hinted int
+	for _, c := range candidates {
+		if c.dict {
+			hinted = max(hinted, c.Hints)
+		}
 	}
-	if my_tags["the"] && next_tags["vowel1st"] {
-		return true
+	for _, c := range candidates {
+		log.Now().Debugf("PreSelect Orig: %s, Word: %s, WordsTags: %v, HasDict: %v, Hints: %d", orig, c.Phonetic, c.Tags, c.dict, c.Hints)
+		if c.dict && c.Hints == hinted {
+			inputmap[c.Phonetic] = [2]uint32{c.key, 0}
+		}
 	}
-	return false
+	log.Now().Debugf("PreSelect Word: %s Now: %v", orig, inputmap)
+	return
 }
 
-func (p *PartsOfSpeechSelectorService) Select(isReverse bool, lang string, sentence []map[string]uint32, languages []string) (ret [][3]string) {
+// Select picks one pronunciation of every word: the candidates are ranked by the
+// selection policy after the homograph model marked its preferred candidate, the
+// best ranked candidate is taken. Hints are the optional part of speech hints of
+// every word, nil when there are none. With the homographs stage disabled, the
+// model is not asked and the policy alone decides. Choices depending on the
+// neighbouring words are left to the cross-word rules.
+func (p *PartsOfSpeechSelectorService) Select(isReverse bool, lang string, sentence []map[string]uint32, languages []string, hints [][]string, disable models.PipelineStages) (ret [][3]string) {
 
 	var input []map[string][2]uint32
+	var origs = make([]string, len(sentence))
+	var intermediate = make([][]candidate, len(sentence))
 
-	for _, words := range sentence {
-		var orig string
-		for word, k := range words {
-			if k == 0 {
-				orig = strings.TrimRight(word, " ")
-				break
-			}
-		}
-		var inputmap = make(map[string][2]uint32)
-		inputmap[orig+" "] = [2]uint32{0, 0}
-		for word, k := range words {
-			if k == 0 {
-				continue
-			}
-			var tags = (*p.repo).LookupTags(isReverse, lang, orig, word)
-			var hasdict bool
-			for _, tag := range log.Error1(helpers.ParseJson[[]string]([]byte(tags))) {
-				if tag == "dict" {
-					hasdict = true
-				}
-			}
-			log.Now().Debugf("PreSelect Orig: %s, Word: %s, WordsTags: %s, HasDict: %v", orig, word, tags, hasdict)
-			if hasdict {
-				inputmap[word] = [2]uint32{k, 0}
-			} else {
-				// set dict flag
-				//inputmap[word] = [2]uint32{k ^ hash.StringHash(0, "dict"), 1}
-			}
+	for i, words := range sentence {
+		var hint []string
+		if i < len(hints) {
+			hint = hints[i]
 		}
-		log.Now().Debugf("PreSelect Word: %s Now: %v", orig, inputmap)
-		input = append(input, inputmap)
+		origs[i], intermediate[i] = p.candidates(isReverse, lang, words, languages, hint)
+		input = append(input, homographInput(origs[i], intermediate[i]))
 	}
 
-	var preferred = (*p.repoai).Select(isReverse, lang, input)
+	var preferred [][4]uint32
+	if !disable.Homographs {
+		preferred = (*p.repoai).Select(isReverse, lang, input)
+	}
 
 	log.Now().Debugf("Preferred: %v", preferred)
 
-	var intermediate []map[[2]string][]string
-	for i, words := range sentence {
+	for i, candidates := range intermediate {
 		var last_preferred, hash_preferred uint32
 		for _, row := range preferred {
 			if row[0] != uint32(i) {
@@ -92,104 +143,53 @@ func (p *PartsOfSpeechSelectorService) Select(isReverse bool, lang string, sente
 			break
 		}
 		log.Now().Debugf("Preferred: %d %d", last_preferred, hash_preferred)
-		var orig string
-		for word, k := range words {
-			if k == 0 {
-				orig = strings.TrimRight(word, " ")
-				break
-			}
-		}
-		var wordstags = make(map[[2]string][]string)
-		for word, k := range words {
-			if k == 0 {
+		for j := range candidates {
+			var c = &candidates[j]
+			if !c.dict {
 				continue
 			}
-			var tags = (*p.repo).LookupTags(isReverse, lang, orig, word)
-			log.Now().Debugf("Orig: %s, Word: %s, WordsTags: %s", orig, word, tags)
-			var tags_parsed = (*p.repoa).TagWord(isReverse, lang, orig, word)
-			if tags != "" && tags != "[]" {
-				tags_parsed = append(tags_parsed, log.Error1(helpers.ParseJson[[]string]([]byte(tags)))...)
-			}
-			for _, lang := range languages {
-				var tags = (*p.repo).LookupTags(isReverse, lang, orig, word)
-				log.Now().Debugf("Orig: %s, Word: %s, WordsTags: %s", orig, word, tags)
-				tags_parsed = append(tags_parsed, (*p.repoa).TagWord(isReverse, lang, orig, word)...)
-				if tags != "" && tags != "[]" {
-					tags_parsed = append(tags_parsed, log.Error1(helpers.ParseJson[[]string]([]byte(tags)))...)
-				}
-			}
-			if last_preferred != 0 && last_preferred == k || hash_preferred == hash.StringHash(0, word) {
-				log.Now().Debugf("Preferred: %v, row: %d %d", word, last_preferred, hash_preferred)
-				tags_parsed = append(tags_parsed, "preferred")
+			if last_preferred != 0 && last_preferred == c.key || hash_preferred == hash.StringHash(0, c.Phonetic) {
+				log.Now().Debugf("Preferred: %v, row: %d %d", c.Phonetic, last_preferred, hash_preferred)
+				c.Preferred = true
+				c.Tags = append(c.Tags, "preferred")
 			}
-			log.Now().Debugf("Orig: %v, Dest: %v, Tags: %v", orig, word, tags_parsed)
-			wordstags[[2]string{orig, word}] = tags_parsed
 		}
-		log.Now().Debugf("WordsTags: %v, Words: %v", wordstags, words)
-		intermediate = append(intermediate, wordstags)
+		p.sort(candidates)
+		log.Now().Debugf("Orig: %v, Candidates: %v", origs[i], candidates)
 	}
 
-outer:
-	for i, mapping := range intermediate {
-		var next_tags = make(map[string]bool)
-		if i+1 < len(intermediate) {
-			var next_mapping = intermediate[i+1]
-			for _, tags := range next_mapping {
-				for _, tag := range tags {
-					next_tags[tag] = true
-				}
-			}
+	for i, candidates := range intermediate {
+		if len(candidates) == 0 {
+			// in case of other bug push an empty word to keep punctuation algined
+			ret = append(ret, [3]string{"", "", "[]"})
+			continue
 		}
-		for words, tags := range mapping {
-			var my_tags = make(map[string]bool)
-			for _, tag := range tags {
-				my_tags[tag] = true
-			}
-			if next_continue_english(lang, isReverse, my_tags, next_tags) {
-				continue
-			}
-			if !my_tags["preferred"] {
-				continue
-			}
-			if !my_tags["dict"] {
-				continue
-			}
-			var orig = words[0]
-			var dest = words[1]
-			ret = append(ret, [3]string{orig, dest, string(log.Error1(helpers.SerializeJson(tags)))})
-			continue outer
-		}
-		for words, tags := range mapping {
-			var my_tags = make(map[string]bool)
-			for _, tag := range tags {
-				my_tags[tag] = true
-			}
-			if next_continue_english(lang, isReverse, my_tags, next_tags) {
-				continue
-			}
-			if !my_tags["dict"] {
-				continue
-			}
-			var orig = words[0]
-			var dest = words[1]
-			ret = append(ret, [3]string{orig, dest, string(log.Error1(helpers.SerializeJson(tags)))})
-			continue outer
+		c := candidates[0]
+		ret = append(ret, [3]string{origs[i], c.Phonetic, string(log.Error1(helpers.SerializeJson(c.Tags)))})
+	}
+	return
+}
+
+// HomographVotes asks the homograph selector about the lexicon candidates of one word, in isolation.
+// A candidate the selector did not get to ask the model about has the vote -1.
+func (p *PartsOfSpeechSelectorService) HomographVotes(isReverse bool, lang string, words map[string]uint32) (ret []models.HomographVote) {
+	orig, candidates := p.candidates(isReverse, lang, words, nil, nil)
+	chosen, votes := (*p.repoai).SelectTrace(isReverse, lang, []map[string][2]uint32{homographInput(orig, candidates)})
+	for _, c := range candidates {
+		if !c.dict {
+			continue
 		}
-		for words, tags := range mapping {
-			var my_tags = make(map[string]bool)
-			for _, tag := range tags {
-				my_tags[tag] = true
+		var vote = -1
+		if len(votes) > 0 {
+			if v, ok := votes[0][hash.StringHash(0, c.Phonetic)]; ok {
+				vote = int(v)
 			}
-			if next_continue_english(lang, isReverse, my_tags, next_tags) {
-				continue
-			}
-			var orig = words[0]
-			var dest = words[1]
-			ret = append(ret, [3]string{orig, dest, string(log.Error1(helpers.SerializeJson(tags)))})
-			continue outer
 		}
-		// in case of other bug push an empty word to keep punctuation algined
-		ret = append(ret, [3]string{"", "", "[]"})
+		ret = append(ret, models.HomographVote{
+			Phonetic: c.Phonetic,
+			Vote:     vote,
+			Chosen:   len(chosen) > 0 && chosen[0][1] == hash.StringHash(0, c.Phonetic),
+		})
 	}
 	return
 }
@@ -198,10 +198,16 @@ func NewPartsOfSpeechSelectorService(di *DependencyInjection) *PartsOfSpeechSele
 	repoiface := (repo.IDictPhonemizerRepository)(Ptr(MustNeed(di, repo.NewDictPhonemizerRepository)))
 	arepoiface := (repo.IAutoTaggerRepository)(Ptr(MustNeed(di, repo.NewAutoTaggerRepository)))
 	airepoiface := (repo.IHashtronHomonymSelectorRepository)(Ptr(MustNeed(di, repo.NewHashtronHomonymSelectorRepository)))
+	var policy = &ranking.DefaultPolicy
+	var configured interfaces.SelectionPolicy
+	if Any(di, &configured) == nil && configured.GetSelectionPolicy() != nil {
+		policy = configured.GetSelectionPolicy()
+	}
 	return &PartsOfSpeechSelectorService{
 		repo:   &repoiface,
 		repoa:  &arepoiface,
 		repoai: &airepoiface,
+		policy: policy,
 	}
 }
 
@@ -1,18 +1,32 @@
 package services
 
 import (
+	"github.com/neurlang/classifier/hash"
+	"github.com/neurlang/goruut/helpers"
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/wordcache"
 	"github.com/neurlang/goruut/repo"
+	"github.com/neurlang/goruut/repo/models"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 import . "github.com/martinarisk/di/dependency_injection"
 
 type IPhonemizeWordService interface {
-	PhonemizeWords(isReverse bool, lang, word string, languages []string) (ret []map[string]uint32, punct [][2]string)
+	PhonemizeWords(isReverse bool, lang, word string, languages []string, disable models.PipelineStages) (ret []map[string]uint32, punct [][2]string, status models.CleanStatus)
 	ExplainWord(isReverse bool, word1, word2, lang string) map[string][]string
+	AlignWord(isReverse bool, lang, word, ipa string) [][2]string
+	TraceWord(isReverse bool, lang, word string) *models.DecodeTrace
+	LookupLexicon(isReverse bool, lang, word string, languages []string) []models.LexiconEntry
 	//CleanWord(isReverse bool, lang, word string) string
 }
 
 type PhonemizeWordService struct {
 	repo *repo.IDictPhonemizerRepository
+	comp *repo.ICompoundSplitterRepository
+	clit *repo.ICliticRepository
 	ai   *repo.IHashtronPhonemizerRepository
 	pre  *repo.IPrePhonWordStepsRepository
 	cach *repo.IWordCachingRepository
@@ -24,9 +38,318 @@ func (p *PhonemizeWordService) ExplainWord(isReverse bool, word1, word2, lang st
 	return (*p.ai).ExplainWord(isReverse, word1, word2, lang)
 }
 
-func (p *PhonemizeWordService) PhonemizeWords(isReverse bool, lang, word string, languages []string) (ret []map[string]uint32, punct [][2]string) {
+func (p *PhonemizeWordService) AlignWord(isReverse bool, lang, word, ipa string) [][2]string {
+	return (*p.ai).AlignWord(isReverse, lang, word, ipa)
+}
+
+// TraceWord cleans the word the way PhonemizeWords does and traces the model decoding of it.
+func (p *PhonemizeWordService) TraceWord(isReverse bool, lang, word string) *models.DecodeTrace {
 	word = (*p.pre).PrePhonemizeWord(isReverse, lang, word)
-	ret = (*p.num).ExpandNumericWord(isReverse, lang, word, languages)
+	word, _, _, _ = (*p.ai).CleanWord(isReverse, word, []string{lang})
+	return (*p.ai).TraceWord(isReverse, lang, word)
+}
+
+// LookupLexicon lists the lexicon entries of the word in the language and the fallback languages.
+func (p *PhonemizeWordService) LookupLexicon(isReverse bool, lang, word string, languages []string) (ret []models.LexiconEntry) {
+	word = (*p.pre).PrePhonemizeWord(isReverse, lang, word)
+	for _, lang := range append([]string{lang}, languages...) {
+		var found []string
+		for _, words := range (*p.repo).LookupWords(isReverse, lang, word) {
+			for k, v := range words {
+				if v != 0 {
+					found = append(found, k)
+				}
+			}
+		}
+		sort.Strings(found)
+		for _, phonetic := range found {
+			tags := (*p.repo).LookupTags(isReverse, lang, word, phonetic)
+			ret = append(ret, models.LexiconEntry{
+				Language: lang,
+				Phonetic: phonetic,
+				Tags:     log.Error1(helpers.ParseJson[[]string]([]byte(tags))),
+			})
+		}
+	}
+	return
+}
+
+// lookupWords looks the word up in the lexicon of the language, then of the fallback languages.
+func (p *PhonemizeWordService) lookupWords(isReverse bool, lang, word string, languages []string, disable models.PipelineStages) (ret []map[string]uint32) {
+	if disable.Lexicon {
+		return nil
+	}
+	ret = (*p.repo).LookupWords(isReverse, lang, word)
+	for _, lang := range languages {
+		if ret != nil {
+			break
+		}
+		ret = (*p.repo).LookupWords(isReverse, lang, word)
+	}
+	return
+}
+
+// lexiconPhonetic returns the pronunciation listed first in the lexicon of the language, "" when none.
+func (p *PhonemizeWordService) lexiconPhonetic(isReverse bool, lang, word string) (ret string) {
+	var best = -1
+	for _, words := range (*p.repo).LookupWords(isReverse, lang, word) {
+		for phonetic, k := range words {
+			if k == 0 {
+				continue
+			}
+			order, _ := (*p.repo).LookupOrder(isReverse, lang, word, phonetic)
+			if best < 0 || order < best || order == best && phonetic < ret {
+				best, ret = order, phonetic
+			}
+		}
+	}
+	return
+}
+
+// compoundKey is the tag key of decompounded pronunciations.
+var compoundKey = hash.StringHash(0, "compound")
+
+// decompound splits a word missing from the lexicon into two or more lexicon words,
+// possibly joined by the linking elements of the language, and returns the joined IPA.
+// The first part keeps its primary stress, the primary stress of the other parts becomes
+// secondary. It returns "" when no split works. Splits into fewer parts win, then splits
+// without linking elements, then splits with longer leading parts.
+func (p *PhonemizeWordService) decompound(isReverse bool, lang, word string) string {
+	rules := (*p.comp).Compound(isReverse, lang)
+	if rules == nil {
+		return ""
+	}
+	runes := []rune(word)
+	if len(runes) < 2*rules.MinPart {
+		return ""
+	}
+	var links = []string{""}
+	for link := range rules.LinkingElements {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if len(links[i]) != len(links[j]) {
+			return len(links[i]) < len(links[j])
+		}
+		return links[i] < links[j]
+	})
+	var phonetic = make(map[string]string)
+	var lookup = func(part string) string {
+		if ipa, ok := phonetic[part]; ok {
+			return ipa
+		}
+		phonetic[part] = p.lexiconPhonetic(isReverse, lang, part)
+		return phonetic[part]
+	}
+	var secondary = strings.NewReplacer("ˈ", "ˌ", "'", "ˌ")
+	var failed = make(map[[2]int]bool)
+	var split func(start, parts int) string
+	split = func(start, parts int) (ret string) {
+		if failed[[2]int{start, parts}] {
+			return ""
+		}
+		defer func() {
+			failed[[2]int{start, parts}] = ret == ""
+		}()
+		if parts == 1 {
+			if len(runes)-start < rules.MinPart {
+				return ""
+			}
+			return secondary.Replace(lookup(string(runes[start:])))
+		}
+		for end := len(runes) - rules.MinPart*(parts-1); end >= start+rules.MinPart; end-- {
+			ipa := lookup(string(runes[start:end]))
+			if ipa == "" {
+				continue
+			}
+			if start > 0 {
+				ipa = secondary.Replace(ipa)
+			}
+			for _, link := range links {
+				if !strings.HasPrefix(string(runes[end:]), link) {
+					continue
+				}
+				if rest := split(end+len([]rune(link)), parts-1); rest != "" {
+					return ipa + rules.LinkingElements[link] + rest
+				}
+			}
+		}
+		return ""
+	}
+	for parts := 2; parts <= rules.MaxParts; parts++ {
+		if ipa := split(0, parts); ipa != "" {
+			log.Now().Debugf("Decompounded %s: %s", word, ipa)
+			return ipa
+		}
+	}
+	return ""
+}
+
+// apostrophes unifies the apostrophe variants with the one used by the clitic rules.
+var apostrophes = strings.NewReplacer("’", "'", "ʼ", "'", "`", "'")
+
+// maxClitics is the maximal number of clitics stripped from one word.
+const maxClitics = 3
+
+// minCliticStem is the minimal length of a stem in letters.
+const minCliticStem = 2
+
+// unclitic strips the clitics of a word missing from the lexicon, looks the stem up and
+// joins the pronunciations of the clitics and of the stem. Fewer clitics win, then longer
+// ones. It returns nil when no clitics of the language match a known stem.
+func (p *PhonemizeWordService) unclitic(isReverse bool, lang, word string, languages []string, disable models.PipelineStages) (ret []map[string]uint32, lpunct, rpunct string) {
+	clitics := (*p.clit).Clitics(isReverse, lang)
+	if len(clitics) == 0 {
+		return nil, "", ""
+	}
+	runes := []rune(word)
+	var l, r = 0, len(runes)
+	for l < r && (unicode.IsPunct(runes[l]) || unicode.IsSymbol(runes[l])) {
+		l++
+	}
+	for l < r && (unicode.IsPunct(runes[r-1]) || unicode.IsSymbol(runes[r-1])) {
+		r--
+	}
+	core := apostrophes.Replace(string(runes[l:r]))
+
+	type stripped struct {
+		stem     string
+		prefixes []*repo.Clitic
+		suffixes []*repo.Clitic
+	}
+	var queue = []stripped{{stem: core}}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		if n := len(s.prefixes) + len(s.suffixes); n > 0 {
+			if m := p.joinClitics(isReverse, lang, s.stem, s.prefixes, s.suffixes, languages, disable); m != nil {
+				m[core+" "] = 0
+				log.Now().Debugf("Stripped clitics of %s: %v", word, m)
+				return []map[string]uint32{m}, string(runes[:l]), string(runes[r:])
+			}
+			if n >= maxClitics {
+				continue
+			}
+		}
+		for i := range clitics {
+			c := &clitics[i]
+			// prefixes are stripped before suffixes, so that each split is visited once
+			if c.Prefix != "" && len(s.suffixes) == 0 && strings.HasPrefix(s.stem, c.Prefix) {
+				stem := s.stem[len(c.Prefix):]
+				if utf8.RuneCountInString(stem) >= minCliticStem {
+					queue = append(queue, stripped{stem, append(append([]*repo.Clitic{}, s.prefixes...), c), nil})
+				}
+			}
+			if c.Suffix != "" && strings.HasSuffix(s.stem, c.Suffix) {
+				stem := s.stem[:len(s.stem)-len(c.Suffix)]
+				if utf8.RuneCountInString(stem) >= minCliticStem {
+					queue = append(queue, stripped{stem, s.prefixes, append(append([]*repo.Clitic{}, s.suffixes...), c)})
+				}
+			}
+		}
+	}
+	return nil, "", ""
+}
+
+// joinClitics looks the stem up and surrounds each of its pronunciations by the clitics,
+// the outermost clitics come first. It returns nil when the stem is unknown.
+func (p *PhonemizeWordService) joinClitics(isReverse bool, lang, stem string, prefixes, suffixes []*repo.Clitic,
+	languages []string, disable models.PipelineStages) map[string]uint32 {
+	var found = p.lookupWords(isReverse, lang, stem, languages, disable)
+	if found == nil && !disable.Model {
+		var anyStem = true
+		for _, c := range append(append([]*repo.Clitic{}, prefixes...), suffixes...) {
+			anyStem = anyStem && c.AnyStem
+		}
+		if anyStem {
+			disable.Clitics = true
+			found, _, _ = p.PhonemizeWords(isReverse, lang, stem, languages, disable)
+		}
+	}
+	if len(found) != 1 {
+		return nil
+	}
+	var ret = make(map[string]uint32)
+	for phonetic, v := range found[0] {
+		if v == 0 || phonetic == "" {
+			continue
+		}
+		var ipa = phonetic
+		for i := len(prefixes) - 1; i >= 0; i-- {
+			ipa = p.cliticIpa(isReverse, lang, prefixes[i], ipa, languages, disable) + ipa
+		}
+		for i := len(suffixes) - 1; i >= 0; i-- {
+			ipa += p.cliticIpa(isReverse, lang, suffixes[i], ipa, languages, disable)
+		}
+		ret[ipa] = v
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// cliticIpa returns the pronunciation of the clitic attached to the stem pronounced as ipa.
+func (p *PhonemizeWordService) cliticIpa(isReverse bool, lang string, c *repo.Clitic, ipa string,
+	languages []string, disable models.PipelineStages) string {
+	if c.Separately {
+		word := strings.ReplaceAll(c.Affix(), "'", "")
+		if ret := p.lexiconPhonetic(isReverse, lang, word); !disable.Lexicon && ret != "" {
+			return ret
+		}
+		disable.Clitics = true
+		found, _, _ := p.PhonemizeWords(isReverse, lang, word, languages, disable)
+		var ret string
+		for _, one := range found {
+			var best string
+			for phonetic, v := range one {
+				if v != 0 && (best == "" || phonetic < best) {
+					best = phonetic
+				}
+			}
+			ret += best
+		}
+		return ret
+	}
+	var stress = "ˈˌ'"
+	for _, sandhi := range c.Sandhi {
+		for _, phone := range sandhi.Phones {
+			if c.Prefix != "" && strings.HasPrefix(strings.TrimLeft(ipa, stress), phone) ||
+				c.Suffix != "" && strings.HasSuffix(strings.TrimRight(ipa, stress), phone) {
+				return strings.ReplaceAll(sandhi.Ipa, "{phone}", phone)
+			}
+		}
+	}
+	return c.Ipa
+}
+
+// foreignWord keeps a word made only of letters foreign to the languages as a word with
+// an empty pronunciation, so that it is reported. Other empty words are dropped.
+func foreignWord(token string, status models.CleanStatus) ([]map[string]uint32, [][2]string, models.CleanStatus) {
+	if !status.ForeignLetters {
+		return nil, nil, status
+	}
+	var isLetter = func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsMark(r)
+	}
+	var l = strings.IndexFunc(token, isLetter)
+	var r = strings.LastIndexFunc(token, isLetter)
+	if l < 0 {
+		return nil, nil, status
+	}
+	_, size := utf8.DecodeRuneInString(token[r:])
+	return []map[string]uint32{{token[l:r+size] + " ": 0, "": 1}}, [][2]string{{token[:l], token[r+size:]}}, status
+}
+
+// PhonemizeWords phonemizes one word, the disabled pipeline stages are skipped.
+// The status tells whether letters foreign to the languages were replaced or dropped.
+func (p *PhonemizeWordService) PhonemizeWords(isReverse bool, lang, word string, languages []string, disable models.PipelineStages) (ret []map[string]uint32, punct [][2]string, status models.CleanStatus) {
+	if !disable.PrePhonemization {
+		word = (*p.pre).PrePhonemizeWord(isReverse, lang, word)
+	}
+	if !disable.Numbers {
+		ret = (*p.num).ExpandNumericWord(isReverse, lang, word, languages)
+	}
 	if ret != nil {
 		// handle numeric words
 		var expanded []map[string]uint32
@@ -38,64 +361,65 @@ func (p *PhonemizeWordService) PhonemizeWords(isReverse bool, lang, word string,
 					break
 				}
 			}
-			result, _ := p.PhonemizeWords(isReverse, lang, numeric_word, languages)
+			result, _, numeric_status := p.PhonemizeWords(isReverse, lang, numeric_word, languages, disable)
 			expanded = append(expanded, result...)
+			status = status.Or(numeric_status)
 		}
-		return expanded, make([][2]string, len(expanded))
+		return expanded, make([][2]string, len(expanded)), status
 	}
 	var lpunct, rpunct string
 	if ret == nil {
-		ret = (*p.repo).LookupWords(isReverse, lang, word)
-		for _, lang := range languages {
-			if ret != nil {
-				break
-			}
-			ret = (*p.repo).LookupWords(isReverse, lang, word)
-		}
+		ret = p.lookupWords(isReverse, lang, word, languages, disable)
+	}
+	if ret == nil && !disable.Clitics {
+		ret, lpunct, rpunct = p.unclitic(isReverse, lang, word, languages, disable)
 	}
 	if ret == nil {
-		word, lpunct, rpunct = (*p.ai).CleanWord(isReverse, word, append([]string{lang}, languages...))
+		var token = word
+		word, lpunct, rpunct, status = (*p.ai).CleanWord(isReverse, word, append([]string{lang}, languages...))
 		if word == "" {
-			return nil, nil
-		}
-		ret = (*p.repo).LookupWords(isReverse, lang, word)
-		for _, lang := range languages {
-			if ret != nil {
-				break
-			}
-			ret = (*p.repo).LookupWords(isReverse, lang, word)
+			return foreignWord(token, status)
 		}
+		ret = p.lookupWords(isReverse, lang, word, languages, disable)
 	}
 	if ret == nil {
-		word, lpunct2, rpunct2 := (*p.ai).CleanWord(isReverse, word, []string{lang})
+		var token = lpunct + word + rpunct
+		word, lpunct2, rpunct2, status2 := (*p.ai).CleanWord(isReverse, word, []string{lang})
+		status = status.Or(status2)
 		if word == "" {
-			return nil, nil
+			return foreignWord(token, status)
 		}
 		lpunct += lpunct2
 		rpunct += rpunct2
-		hash := (*p.cach).HashWord(isReverse, lang, word)
-		r := (*p.cach).LoadWord(hash)
-		if r == nil || len(r) == 0 {
-			ret = (*p.ai).PhonemizeWords(isReverse, lang, word)
-			for i, one := range ret {
-				//rett := (*p.ai).PhonemizeWord(isReverse, lang, one[0])
-				//if len(rett) > 0 {
-				//	one = rett
-				//	ret[i] = rett
-				//}
-				(*p.cach).StoreWord(one, hash+uint32(i))
-			}
+		// a compound is split by the lexicon, only the words of the model are cached
+		var compound string
+		if !disable.Compounds && !disable.Lexicon {
+			compound = p.decompound(isReverse, lang, word)
+		}
+		if compound != "" {
+			ret = []map[string]uint32{{word + " ": 0, compound: compoundKey}}
+		} else if disable.Model {
+			// unknown word without the model: keep the word, leave its pronunciation empty
+			ret = []map[string]uint32{{word + " ": 0, "": 1}}
 		} else {
-			ret = append(ret, r)
-			for i := uint32(1); true; i++ {
-				r = (*p.cach).LoadWord(hash + i)
-				if r == nil || len(r) == 0 {
-					break
+			var key = wordcache.Key{
+				Reverse:   isReverse,
+				Language:  lang,
+				Languages: strings.Join(languages, ","),
+				Model:     (*p.ai).ModelVersion(isReverse, lang),
+				Word:      word,
+			}
+			if !disable.Cache {
+				ret = (*p.cach).LoadWord(key)
+			}
+			if len(ret) == 0 {
+				ret = (*p.ai).PhonemizeWords(isReverse, lang, word)
+				if !disable.Cache {
+					(*p.cach).StoreWord(key, ret)
 				}
-				ret = append(ret, r)
 			}
 		}
-	} else if (*p.tag).IsCrossDictWord(isReverse, lang, word) {
+	} else if !disable.Model && (*p.tag).IsCrossDictWord(isReverse, lang, word) {
 		ret2 := (*p.ai).PhonemizeWords(isReverse, lang, word)
 		for i, r := range ret2 {
 			for k, v := range r {
@@ -126,6 +450,8 @@ func NewPhonemizeWordService(di *DependencyInjection) *PhonemizeWordService {
 	cach_repo_iface := (repo.IWordCachingRepository)(Ptr(MustNeed(di, repo.NewWordCachingRepository)))
 	tag_repo_iface := (repo.IAutoTaggerRepository)(Ptr(MustNeed(di, repo.NewAutoTaggerRepository)))
 	num_repo_iface := (repo.INumToWordsRepository)(Ptr(MustNeed(di, repo.NewNumToWordsRepository)))
+	comp_repo_iface := (repo.ICompoundSplitterRepository)(Ptr(MustNeed(di, repo.NewCompoundSplitterRepository)))
+	clit_repo_iface := (repo.ICliticRepository)(Ptr(MustNeed(di, repo.NewCliticRepository)))
 
 	return &PhonemizeWordService{
 		repo: &repoiface,
@@ -134,6 +460,8 @@ func NewPhonemizeWordService(di *DependencyInjection) *PhonemizeWordService {
 		cach: &cach_repo_iface,
 		tag:  &tag_repo_iface,
 		num:  &num_repo_iface,
+		comp: &comp_repo_iface,
+		clit: &clit_repo_iface,
 	}
 }
 
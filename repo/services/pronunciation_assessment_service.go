@@ -0,0 +1,197 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/pkg/phonealign"
+	"github.com/neurlang/goruut/repo/models"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IPronunciationAssessmentService interface {
+	Assess(expected [][]string, heard string) (words []models.AssessedWord, score float64)
+}
+
+type PronunciationAssessmentService struct {
+	costs *phonealign.Costs[string, string]
+}
+
+// phones normalizes an IPA string and splits it into phones, dropping word separators,
+// punctuation and, unless stress is set, stress marks.
+func (s *PronunciationAssessmentService) phones(ipa string, stress bool) (ret []string) {
+	ipa = strings.NewReplacer("'", "ˈ", "ɡ", "g").Replace(norm.NFC.String(ipa))
+	for _, phone := range phonealign.Phones(ipa) {
+		r, _ := utf8.DecodeRuneInString(phone)
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || r == '|' || (!stress && phonealign.IsStress(r)) {
+			continue
+		}
+		ret = append(ret, phone)
+	}
+	return
+}
+
+// best aligns the heard phones to every alternative and returns the closest one.
+func (s *PronunciationAssessmentService) best(alternatives []string, heard []string, stress bool) (expected []string, pairs []phonealign.Pair, distance float64) {
+	for i, alternative := range alternatives {
+		phones := s.phones(alternative, stress)
+		p, d := phonealign.Align(phones, heard, *s.costs)
+		if i == 0 || d < distance {
+			expected, pairs, distance = phones, p, d
+		}
+	}
+	return
+}
+
+// split aligns the heard utterance to the first alternatives of all words and cuts it
+// into one span per word. Heard phones inserted between words go to the preceding word.
+func (s *PronunciationAssessmentService) split(expected [][]string, heard []string, stress bool) (spans [][]string) {
+	var sequence []string
+	var owner []int
+	for i, alternatives := range expected {
+		if len(alternatives) == 0 {
+			continue
+		}
+		for _, phone := range s.phones(alternatives[0], stress) {
+			sequence = append(sequence, phone)
+			owner = append(owner, i)
+		}
+	}
+	spans = make([][]string, len(expected))
+	pairs, _ := phonealign.Align(sequence, heard, *s.costs)
+	var current int
+	for _, pair := range pairs {
+		if pair.A >= 0 {
+			current = owner[pair.A]
+		}
+		if pair.B >= 0 {
+			spans[current] = append(spans[current], heard[pair.B])
+		}
+	}
+	return
+}
+
+// word assesses the heard phones of one expected word.
+func (s *PronunciationAssessmentService) word(index int, alternatives []string, heard []string, stress bool) (word models.AssessedWord, distance, length float64) {
+	expected, pairs, distance := s.best(alternatives, heard, stress)
+	for _, phone := range expected {
+		length += s.costs.Delete(phone)
+	}
+	word = models.AssessedWord{
+		Index:    index,
+		Expected: strings.Join(expected, ""),
+		Heard:    strings.Join(heard, ""),
+		Label:    phonealign.Substitute.String(),
+		Score:    score(distance, length),
+	}
+	switch {
+	case len(heard) == 0:
+		word.Label = phonealign.Delete.String()
+	case distance == 0:
+		word.Label = phonealign.Match.String()
+	}
+	for _, pair := range pairs {
+		var phone = models.AssessedPhone{Label: pair.Op.String()}
+		if pair.A >= 0 {
+			phone.Expected = expected[pair.A]
+		}
+		if pair.B >= 0 {
+			phone.Heard = heard[pair.B]
+		}
+		word.Phones = append(word.Phones, phone)
+	}
+	return word, distance, length
+}
+
+// inserted assesses a heard word which matches no expected word.
+func (s *PronunciationAssessmentService) inserted(heard []string) (word models.AssessedWord, distance float64) {
+	word = models.AssessedWord{
+		Index: -1,
+		Heard: strings.Join(heard, ""),
+		Label: phonealign.Insert.String(),
+	}
+	for _, phone := range heard {
+		word.Phones = append(word.Phones, models.AssessedPhone{Heard: phone, Label: phonealign.Insert.String()})
+		distance += s.costs.Insert(phone)
+	}
+	return
+}
+
+// score maps the distance to the range 0 to 1, where 1 is a perfect pronunciation.
+// The length is the cost of deleting every expected phone.
+func score(distance, length float64) float64 {
+	if length == 0 {
+		if distance == 0 {
+			return 1
+		}
+		return 0
+	}
+	return max(0, 1-distance/length)
+}
+
+// Assess compares the heard IPA to the expected words, each given by its alternative
+// pronunciations with the preferred one first. When the heard IPA is split into words
+// by spaces, the words are aligned first, so that skipped and extra words are labeled.
+// Otherwise the heard utterance is cut into the expected words by a phone alignment.
+// Stress is assessed only when the heard IPA marks stress.
+func (s *PronunciationAssessmentService) Assess(expected [][]string, heard string) (words []models.AssessedWord, total float64) {
+	var stress = strings.ContainsFunc(heard, phonealign.IsStress) || strings.Contains(heard, "'")
+	var heardWords [][]string
+	for _, word := range strings.Fields(heard) {
+		if phones := s.phones(word, stress); len(phones) > 0 {
+			heardWords = append(heardWords, phones)
+		}
+	}
+	var distance, length float64
+	var add = func(word models.AssessedWord, d, l float64) {
+		words = append(words, word)
+		distance += d
+		length += l
+	}
+	if len(heardWords) <= 1 && len(expected) > 1 {
+		for i, span := range s.split(expected, collapse(heardWords), stress) {
+			add(s.word(i, expected[i], span, stress))
+		}
+		return words, score(distance, length)
+	}
+	var indices = make([]int, len(expected))
+	for i := range indices {
+		indices[i] = i
+	}
+	pairs, _ := phonealign.Align(indices, heardWords, phonealign.Costs[int, []string]{
+		Subst: func(i int, heard []string) float64 {
+			phones, _, d := s.best(expected[i], heard, stress)
+			return min(1, d/float64(max(1, len(phones), len(heard))))
+		},
+	})
+	for _, pair := range pairs {
+		switch {
+		case pair.A < 0:
+			word, d := s.inserted(heardWords[pair.B])
+			add(word, d, 0)
+		case pair.B < 0:
+			add(s.word(pair.A, expected[pair.A], nil, stress))
+		default:
+			add(s.word(pair.A, expected[pair.A], heardWords[pair.B], stress))
+		}
+	}
+	return words, score(distance, length)
+}
+
+func collapse[T any](slice [][]T) (ret []T) {
+	for _, subslice := range slice {
+		ret = append(ret, subslice...)
+	}
+	return
+}
+
+func NewPronunciationAssessmentService(di *DependencyInjection) *PronunciationAssessmentService {
+	costs := phonealign.PhoneCosts()
+	return &PronunciationAssessmentService{
+		costs: &costs,
+	}
+}
+
+var _ IPronunciationAssessmentService = &PronunciationAssessmentService{}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/repo"
+	"github.com/neurlang/goruut/repo/models"
+	"unicode"
+	"unicode/utf8"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IPunctuationService interface {
+	Tokens(isReverse bool, lang, punct string) []models.PunctuationToken
+}
+
+type PunctuationService struct {
+	repo *repo.IPunctuationRepository
+}
+
+// Tokens splits the punctuation around a word into marks, the longest mark known to the
+// language wins. Spaces are skipped, unknown characters are of the other type without a break.
+func (p *PunctuationService) Tokens(isReverse bool, lang, punct string) (ret []models.PunctuationToken) {
+	if punct == "" {
+		return nil
+	}
+	marks := (*p.repo).Punctuation(isReverse, lang)
+	var longest int
+	for mark := range marks {
+		longest = max(longest, utf8.RuneCountInString(mark))
+	}
+	runes := []rune(punct)
+outer:
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		for n := min(longest, len(runes)-i); n > 0; n-- {
+			if kind, ok := marks[string(runes[i:i+n])]; ok {
+				ret = append(ret, models.PunctuationToken{Text: string(runes[i : i+n]), Punctuation: kind})
+				i += n
+				continue outer
+			}
+		}
+		ret = append(ret, models.PunctuationToken{
+			Text:        string(runes[i]),
+			Punctuation: models.Punctuation{Type: models.PunctuationOther, Break: models.BreakNone},
+		})
+		i++
+	}
+	return
+}
+
+func NewPunctuationService(di *DependencyInjection) *PunctuationService {
+	repoiface := (repo.IPunctuationRepository)(Ptr(MustNeed(di, repo.NewPunctuationRepository)))
+	return &PunctuationService{
+		repo: &repoiface,
+	}
+}
+
+var _ IPunctuationService = &PunctuationService{}
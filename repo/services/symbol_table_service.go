@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/neurlang/goruut/pkg/symboltable"
+	"github.com/neurlang/goruut/repo"
+)
+
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ISymbolTableService interface {
+	Encode(name string, phonetic []string, punct [][2]string) (words []symboltable.Word, ids []int, ok bool)
+	Store(name string, table *symboltable.Table) error
+	Names() []string
+}
+
+type SymbolTableService struct {
+	repo *repo.ISymbolTableRepository
+}
+
+// Encode maps the phonetic words to the IDs of the named table, ok is false when there is no such table.
+func (s *SymbolTableService) Encode(name string, phonetic []string, punct [][2]string) (words []symboltable.Word, ids []int, ok bool) {
+	table := (*s.repo).GetTable(name)
+	if table == nil {
+		return nil, nil, false
+	}
+	for _, word := range phonetic {
+		words = append(words, table.Phones(word))
+	}
+	return words, table.Sentence(words, punct), true
+}
+
+func (s *SymbolTableService) Store(name string, table *symboltable.Table) error {
+	return (*s.repo).SetTable(name, table)
+}
+
+func (s *SymbolTableService) Names() []string {
+	return (*s.repo).Names()
+}
+
+func NewSymbolTableService(di *DependencyInjection) *SymbolTableService {
+	repoiface := (repo.ISymbolTableRepository)(Ptr(MustNeed(di, repo.NewSymbolTableRepository)))
+
+	return &SymbolTableService{
+		repo: &repoiface,
+	}
+}
+
+var _ ISymbolTableService = &SymbolTableService{}
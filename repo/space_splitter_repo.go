@@ -110,10 +110,17 @@ func (p *SpaceSplitterRepository) LoadLanguage(isReverse bool, lang string) {
 func NewSpaceSplitterRepository(di *DependencyInjection) *SpaceSplitterRepository {
 	getter := MustAny[interfaces.DictGetter](di)
 	langs := make(spacesplitlanguages)
+	mut := &sync.RWMutex{}
+	forgetOnReload(di, func(key string) {
+		mut.Lock()
+		defer mut.Unlock()
+		delete(langs, key)
+	})
+
 	return &SpaceSplitterRepository{
 		getter: &getter,
 		lang:   &langs,
-		mut:    &sync.RWMutex{},
+		mut:    mut,
 	}
 }
 
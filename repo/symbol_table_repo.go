@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"github.com/neurlang/goruut/helpers/log"
+	"github.com/neurlang/goruut/pkg/symboltable"
+	"github.com/neurlang/goruut/repo/interfaces"
+	"sort"
+	"sync"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ISymbolTableRepository interface {
+	GetTable(name string) *symboltable.Table
+	SetTable(name string, table *symboltable.Table) error
+	Names() []string
+}
+type SymbolTableRepository struct {
+	mut    *sync.RWMutex
+	tables *map[string]*symboltable.Table
+}
+
+func (r *SymbolTableRepository) GetTable(name string) *symboltable.Table {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return (*r.tables)[name]
+}
+
+// SetTable stores the table under the name, a nil table removes it.
+func (r *SymbolTableRepository) SetTable(name string, table *symboltable.Table) error {
+	if table != nil {
+		if err := table.Validate(); err != nil {
+			return err
+		}
+	}
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if table == nil {
+		delete(*r.tables, name)
+		log.Now().Infof("Symbol table %s removed", name)
+		return nil
+	}
+	(*r.tables)[name] = table
+	log.Now().Infof("Symbol table %s stored with %d symbols", name, len(table.PhonemeIdMap))
+	return nil
+}
+
+func (r *SymbolTableRepository) Names() (ret []string) {
+	r.mut.RLock()
+	for name := range *r.tables {
+		ret = append(ret, name)
+	}
+	r.mut.RUnlock()
+	sort.Strings(ret)
+	return
+}
+
+func NewSymbolTableRepository(di *DependencyInjection) *SymbolTableRepository {
+	tables := make(map[string]*symboltable.Table)
+	r := &SymbolTableRepository{
+		mut:    &sync.RWMutex{},
+		tables: &tables,
+	}
+
+	var config interfaces.SymbolTables
+	if Any(di, &config) == nil {
+		for name, table := range config.GetSymbolTables() {
+			if err := r.SetTable(name, table); err != nil {
+				log.Now().Errorf("Symbol table %s not loaded: %v", name, err)
+			}
+		}
+	}
+	return r
+}
+
+var _ ISymbolTableRepository = &SymbolTableRepository{}
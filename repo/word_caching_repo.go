@@ -1,115 +1,85 @@
 package repo
 
 import (
-	"crypto/rand"
-	"encoding/binary"
-	"github.com/maypok86/otter"
-	"github.com/neurlang/classifier/hash"
 	"github.com/neurlang/goruut/helpers/log"
-	"time"
+	"github.com/neurlang/goruut/pkg/wordcache"
+	"github.com/neurlang/goruut/repo/interfaces"
 )
 import . "github.com/martinarisk/di/dependency_injection"
 
 type IWordCachingRepository interface {
-	HashWord(isReverse bool, lang, word string) uint32
-	LoadWord(hash uint32) map[string]uint32
-	StoreWord(one map[string]uint32, hash uint32)
+	LoadWord(key wordcache.Key) []map[string]uint32
+	StoreWord(key wordcache.Key, parts []map[string]uint32)
+	ForgetLanguage(lang string)
+	Stats() (memory, disk wordcache.Stats)
 }
+
+// WordCachingRepository keeps the words in memory, and optionally in a file which
+// outlives restarts and warms the memory on start.
 type WordCachingRepository struct {
-	seed  uint32
-	cache otter.Cache[uint32, string]
+	cache *wordcache.Cache
+	disk  *wordcache.Disk
 }
 
-func (r WordCachingRepository) LoadWord(hash uint32) (word map[string]uint32) {
-	value, _ := r.cache.Get(hash)
-	if value == "" {
-		return nil
+func (r WordCachingRepository) LoadWord(key wordcache.Key) []map[string]uint32 {
+	if parts := r.cache.Load(key); parts != nil {
+		return parts
 	}
-	word = make(map[string]uint32)
-	length := binary.LittleEndian.Uint32([]byte(value[0:4]))
-	end := 4 + length*16
-	for i := uint32(0); i < length; i++ {
-		k := binary.LittleEndian.Uint64([]byte(value[3*i+4 : 3*i+12]))
-		l := binary.LittleEndian.Uint32([]byte(value[3*i+12 : 3*i+16]))
-		m := binary.LittleEndian.Uint32([]byte(value[3*i+16 : 3*i+20]))
-		src := value[end : end+l]
-		end += l
-		dst := value[end : end+m]
-		end += m
-		word[src] = 0
-		word[dst] = uint32(k)
+	parts := r.disk.Load(key)
+	if parts != nil {
+		r.cache.Store(key, parts)
 	}
-	return word
+	return parts
 }
 
-func (r WordCachingRepository) StoreWord(value map[string]uint32, hash uint32) {
-
-	var buf, data []byte
-	var num4 [4]byte
-	var num8 [8]byte
-	var has0 bool
-	var str0 string
-	for k, v := range value {
-		if v == 0 {
-			has0 = true
-			str0 = k
-			break
-		}
-	}
-	if has0 {
-		binary.LittleEndian.PutUint32(num4[:], uint32(len(value)-1))
-	} else {
-		binary.LittleEndian.PutUint32(num8[:], uint32(len(value)))
-	}
-	buf = append(buf, num4[:]...)
-
-	for v, k := range value {
-		if k == 0 {
-			continue
-		}
-		binary.LittleEndian.PutUint64(num8[:], uint64(k))
-		buf = append(buf, num8[:]...)
-		binary.LittleEndian.PutUint32(num4[:], uint32(len(str0)))
-		buf = append(buf, num4[:]...)
-		binary.LittleEndian.PutUint32(num4[:], uint32(len(v)))
-		buf = append(buf, num4[:]...)
-		data = append(data, []byte(str0)...)
-		data = append(data, []byte(v)...)
-	}
+func (r WordCachingRepository) StoreWord(key wordcache.Key, parts []map[string]uint32) {
+	r.cache.Store(key, parts)
+	log.Error0(r.disk.Store(key, parts))
+}
 
-	val := string(buf) + string(data)
+// ForgetLanguage drops the words whose result depends on the language.
+func (r WordCachingRepository) ForgetLanguage(lang string) {
+	r.cache.Forget(lang)
+	log.Error0(r.disk.Forget(lang))
+}
 
-	r.cache.Set(hash, val)
+// Stats returns the counters of the memory and of the file.
+func (r WordCachingRepository) Stats() (memory, disk wordcache.Stats) {
+	return r.cache.Stats(), r.disk.Stats()
 }
 
-func (r WordCachingRepository) HashWord(isReverse bool, lang, word string) uint32 {
+func NewWordCachingRepository(di *DependencyInjection) *WordCachingRepository {
 
-	str := word + "\x00" + lang
-	if isReverse {
-		str += "_reverse"
+	var size, ttl = wordcache.Default, wordcache.DefaultTTL
+	var file string
+	var limit int64
+	var conf interfaces.WordCache
+	if Any(di, &conf) == nil {
+		size, ttl = conf.GetWordCacheSize(), conf.GetWordCacheTTL()
+		file, limit = conf.GetWordCacheFile(), conf.GetWordCacheFileLimit()
 	}
 
-	return hash.StringHash(r.seed, str)
-}
+	cache := log.Error1(wordcache.New(size, ttl))
 
-func NewWordCachingRepository(di *DependencyInjection) *WordCachingRepository {
-
-	var buf [4]byte
-	rand.Read(buf[:])
-	seed := binary.LittleEndian.Uint32(buf[:])
+	var disk *wordcache.Disk
+	if file != "" {
+		disk = log.Error1(wordcache.OpenDisk(file, limit))
+		disk.Warm(cache.Capacity(), cache.Store)
+		log.Now().Infof("Word cache %s warmed %d words", file, cache.Stats().Words)
+	}
 
-	// create a cache with capacity equal to 10000 elements
-	cache := log.Error1(otter.MustBuilder[uint32, string](10_000).
-		CollectStats().
-		Cost(func(key uint32, value string) uint32 {
-			return 1
-		}).
-		WithTTL(time.Hour).
-		Build())
+	// the words of an unloaded language are still right, the file keeps them
+	onReload(di, cache.Forget)
+	var notifier interfaces.ReloadNotifier
+	if Any(di, &notifier) == nil {
+		notifier.OnReload(func(lang string) {
+			log.Error0(disk.Forget(lang))
+		})
+	}
 
 	return &WordCachingRepository{
-		seed:  seed,
 		cache: cache,
+		disk:  disk,
 	}
 }
 
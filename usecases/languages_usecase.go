@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"github.com/neurlang/goruut/models/responses"
+	"github.com/neurlang/goruut/pkg/registry"
+	"github.com/neurlang/goruut/repo/services"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ILanguagesUsecase interface {
+	List() responses.Languages
+}
+
+type LanguagesUsecase struct {
+	lang services.ILanguageService
+}
+
+// List returns the languages compiled in.
+func (l *LanguagesUsecase) List() (resp responses.Languages) {
+	resp.Languages = l.lang.Languages()
+	if resp.Languages == nil {
+		resp.Languages = []registry.Language{}
+	}
+	return
+}
+
+func NewLanguagesUsecase(di *DependencyInjection) *LanguagesUsecase {
+	lang := MustNeed(di, services.NewLanguageService)
+
+	return &LanguagesUsecase{
+		lang: &lang,
+	}
+}
+
+var _ ILanguagesUsecase = &LanguagesUsecase{}
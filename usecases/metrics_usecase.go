@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"github.com/neurlang/goruut/models/responses"
+	"github.com/neurlang/goruut/pkg/wordcache"
+	"github.com/neurlang/goruut/repo/services"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IMetricsUsecase interface {
+	Metrics() responses.Metrics
+}
+
+type MetricsUsecase struct {
+	met services.IMetricsService
+}
+
+// Metrics returns the counters of the server.
+func (m *MetricsUsecase) Metrics() (resp responses.Metrics) {
+	memory, disk := m.met.WordCache()
+	resp.WordCache = map[string]wordcache.Stats{"memory": memory, "disk": disk}
+	return
+}
+
+func NewMetricsUsecase(di *DependencyInjection) *MetricsUsecase {
+	met := MustNeed(di, services.NewMetricsService)
+
+	return &MetricsUsecase{
+		met: &met,
+	}
+}
+
+var _ IMetricsUsecase = &MetricsUsecase{}
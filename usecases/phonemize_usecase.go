@@ -8,30 +8,170 @@ import (
 	"github.com/neurlang/goruut/models/requests"
 	"github.com/neurlang/goruut/models/responses"
 	"github.com/neurlang/goruut/repo/interfaces"
+	"github.com/neurlang/goruut/repo/models"
 	"github.com/neurlang/goruut/repo/services"
 	"strings"
 	"sync/atomic"
+	"unicode"
 )
 import . "github.com/martinarisk/di/dependency_injection"
 
 type IPhonemizeUsecase interface {
 	Sentence(requests.PhonemizeSentence) responses.PhonemizeSentence
 	Word(requests.ExplainWord) responses.ExplainWord
+	Assess(requests.AssessPronunciation) responses.AssessPronunciation
 }
 
 type PhonemizeUsecase struct {
 	service services.ISplitWordsService
 	phon    services.IPhonemizeWordService
 	sel     services.IPartsOfSpeechSelectorService
+	cross   services.ICrossWordService
+	punct   services.IPunctuationService
 	flavor  services.IIpaFlavorService
 	sent    services.ISentencizerService
+	sym     services.ISymbolTableService
+	assess  services.IPronunciationAssessmentService
+	lang    services.ILanguageService
 	maxwrds uint64
+	flavors *map[string][]string
 }
 
 func (p *PhonemizeUsecase) Word(r requests.ExplainWord) (resp responses.ExplainWord) {
-	return responses.ExplainWord{
-		Rules: p.phon.ExplainWord(r.IsReverse, r.CleanWord, r.Phonetic, r.Language),
+	r.Init()
+	if err := p.lang.Check(r.IsReverse, r.Language, r.Languages); err != nil {
+		resp.ErrorUnsupportedLanguage = err.Error()
+		return
+	}
+	resp.Rules = p.phon.ExplainWord(r.IsReverse, r.CleanWord, r.Phonetic, r.Language)
+	resp.Lexicon = p.phon.LookupLexicon(r.IsReverse, r.Language, r.CleanWord, r.Languages)
+	resp.Trace = p.phon.TraceWord(r.IsReverse, r.Language, r.CleanWord)
+
+	words, _, _ := p.phon.PhonemizeWords(r.IsReverse, r.Language, r.CleanWord, r.Languages, models.PipelineStages{})
+	if len(words) == 1 && len(words[0]) > 2 {
+		resp.Homograph = p.sel.HomographVotes(r.IsReverse, r.Language, words[0])
+	}
+
+	if r.Phonetic != "" && resp.Trace != nil {
+		resp.Divergence = p.divergence(r.IsReverse, r.Language, resp.Trace, r.Phonetic)
+	}
+	return
+}
+
+// divergence aligns the expected pronunciation to the traced word and returns
+// the first segment where the model output differs, nil when none differs.
+func (p *PhonemizeUsecase) divergence(isReverse bool, lang string, trace *models.DecodeTrace, expected string) *models.Divergence {
+	var chunks = p.phon.AlignWord(isReverse, lang, trace.Word, expected)
+	for i, chunk := range chunks {
+		var got string
+		if i < len(trace.Output) {
+			got = strings.ReplaceAll(trace.Output[i], "_", "")
+		}
+		if chunk[1] != got {
+			return &models.Divergence{
+				Index:    i,
+				Segment:  chunk[0],
+				Expected: chunk[1],
+				Got:      got,
+			}
+		}
+	}
+	if len(trace.Output) > len(chunks) {
+		return &models.Divergence{
+			Index: len(chunks),
+			Got:   strings.ReplaceAll(strings.Join(trace.Output[len(chunks):], ""), "_", ""),
+		}
+	}
+	return nil
+}
+
+// Assess phonemizes the sentence and grades the heard IPA against it. Every lexicon
+// pronunciation of a word is accepted as correct, not only the one phonemized.
+func (p *PhonemizeUsecase) Assess(r requests.AssessPronunciation) (resp responses.AssessPronunciation) {
+	var sentence = requests.PhonemizeSentence{
+		IpaFlavors: r.IpaFlavors,
+		Language:   r.Language,
+		Languages:  r.Languages,
+		Sentence:   r.Sentence,
+	}
+	sentence.Init()
+	phonemized := p.Sentence(sentence)
+	if phonemized.ErrorWordLimitExceeded || phonemized.ErrorUnsupportedLanguage != "" {
+		resp.ErrorWordLimitExceeded = phonemized.ErrorWordLimitExceeded
+		resp.ErrorUnsupportedLanguage = phonemized.ErrorUnsupportedLanguage
+		return
+	}
+	var flavors = p.ipaFlavors(&sentence)
+	var expected = make([][]string, len(phonemized.Words))
+	for i, word := range phonemized.Words {
+		var seen = map[string]bool{word.Phonetic: true}
+		expected[i] = append(expected[i], word.Phonetic)
+		for _, entry := range p.phon.LookupLexicon(false, sentence.Language, word.CleanWord, sentence.Languages) {
+			var phonetic = entry.Phonetic
+			for _, flavor := range flavors {
+				phonetic = p.flavor.Apply(flavor, phonetic)
+			}
+			if !seen[phonetic] {
+				seen[phonetic] = true
+				expected[i] = append(expected[i], phonetic)
+			}
+		}
+	}
+	words, score := p.assess.Assess(expected, r.Heard)
+	resp.Score = score
+	resp.Words = []responses.AssessPronunciationWord{}
+	for _, word := range words {
+		var clean string
+		if word.Index >= 0 {
+			clean = phonemized.Words[word.Index].CleanWord
+		}
+		resp.Words = append(resp.Words, responses.AssessPronunciationWord{
+			CleanWord:    clean,
+			AssessedWord: word,
+		})
+	}
+	return
+}
+
+// ipaFlavors returns the flavor chain for the request, the configured default
+// flavors of the language (or of "*") apply when the request names none.
+func (p *PhonemizeUsecase) ipaFlavors(r *requests.PhonemizeSentence) []string {
+	if r.Disable.Flavors {
+		return nil
+	}
+	var flavors = r.IpaFlavors
+	if flavors == nil {
+		flavors = (*p.flavors)[r.Language]
 	}
+	if flavors == nil {
+		flavors = (*p.flavors)["*"]
+	}
+	if flavors == nil {
+		return nil
+	}
+	return p.flavor.Resolve(r.Language, flavors)
+}
+
+// posMarkup splits the inline hints off a word written as word{hint|hint}, punctuation
+// may follow the closing brace. Hints are separated by "|" or ",".
+func posMarkup(word string) (string, []string) {
+	open := strings.LastIndexByte(word, '{')
+	end := strings.LastIndexByte(word, '}')
+	if open <= 0 || end < open {
+		return word, nil
+	}
+	for _, r := range word[end+1:] {
+		if !unicode.IsPunct(r) {
+			return word, nil
+		}
+	}
+	var hints []string
+	for _, hint := range strings.FieldsFunc(word[open+1:end], func(r rune) bool {
+		return r == '|' || r == ','
+	}) {
+		hints = append(hints, strings.TrimSpace(hint))
+	}
+	return word[:open] + word[end+1:], hints
 }
 
 func collapse[T any](slice [][]T) (ret []T) {
@@ -43,7 +183,13 @@ func collapse[T any](slice [][]T) (ret []T) {
 
 func (p *PhonemizeUsecase) Sentence(r requests.PhonemizeSentence) (resp responses.PhonemizeSentence) {
 	r.Init()
+	if err := p.lang.Check(r.IsReverse, r.Language, r.Languages); err != nil {
+		resp.ErrorUnsupportedLanguage = err.Error()
+		resp.Init()
+		return
+	}
 
+	var flavors = p.ipaFlavors(&r)
 	var sentences = []string{r.Sentence}
 	if r.SplitSentences && !r.IsReverse {
 		sentences = p.sent.Split(r.Language, r.Sentence)
@@ -51,9 +197,21 @@ func (p *PhonemizeUsecase) Sentence(r requests.PhonemizeSentence) (resp response
 	var totalLenSplitted atomic.Uint64
 	var ipa_flavored = make([][][3]string, len(sentences), len(sentences))
 	var punctuation = make([][][2]string, len(sentences), len(sentences))
+	var alignments = make([][][][2]string, len(sentences), len(sentences))
+	var fired = make([][][]string, len(sentences), len(sentences))
+	var stray = make([]string, len(sentences), len(sentences))
+	var cleaned = make([][]models.CleanStatus, len(sentences), len(sentences))
+	var splitted_all = make([][]string, len(sentences), len(sentences))
+	var offsets = make([]int, len(sentences), len(sentences))
+	for j := range sentences {
+		splitted_all[j] = p.service.SplitWords(r.IsReverse, r.Language, sentences[j])
+		if j+1 < len(sentences) {
+			offsets[j+1] = offsets[j] + len(splitted_all[j])
+		}
+	}
 	parallel.ForEach(len(sentences), 10, func(j int) {
 
-		splitted := p.service.SplitWords(r.IsReverse, r.Language, sentences[j])
+		splitted := splitted_all[j]
 
 		totalLenSplitted.Add(uint64(len(splitted)))
 
@@ -63,31 +221,76 @@ func (p *PhonemizeUsecase) Sentence(r requests.PhonemizeSentence) (resp response
 
 		var phonemized_all = make([][]map[string]uint32, len(splitted), len(splitted))
 		var punctuation_all = make([][][2]string, len(splitted), len(splitted))
+		var hints_all = make([][][]string, len(splitted), len(splitted))
+		var cleaned_all = make([][]models.CleanStatus, len(splitted), len(splitted))
+		var loose_all = make([]string, len(splitted), len(splitted))
 
 		parallel.ForEach(len(splitted), 1000, func(i int) {
 			word := splitted[i]
-			words, punct := p.phon.PhonemizeWords(r.IsReverse, r.Language, word, r.Languages)
+			hints := r.PosHints[offsets[j]+i]
+			if r.PosMarkup {
+				var markup []string
+				word, markup = posMarkup(word)
+				hints = append(hints[:len(hints):len(hints)], markup...)
+			}
+			words, punct, status := p.phon.PhonemizeWords(r.IsReverse, r.Language, word, r.Languages, r.Disable)
+			if words == nil && r.PunctuationTokens {
+				loose_all[i] = word
+			}
 			phonemized_all[i] = words
 			punctuation_all[i] = punct
-			log.Now().Debugf("Word: %s, Words: %v", word, words)
+			hints_all[i] = make([][]string, len(words))
+			cleaned_all[i] = make([]models.CleanStatus, len(words))
+			for k := range words {
+				hints_all[i][k] = hints
+				cleaned_all[i][k] = status
+			}
+			log.Now().Debugf("Word: %s, Words: %v, Hints: %v", word, words, hints)
 		})
 		var phonemized = collapse(phonemized_all)
 		punctuation[j] = collapse(punctuation_all)
+		cleaned[j] = collapse(cleaned_all)
+		if r.PunctuationTokens {
+			stray[j] = attachLoose(punctuation[j], phonemized_all, loose_all)
+		}
 
 		if totalLenSplitted.Load() > p.maxwrds {
 			return
 		}
 
-		parts_of_speech_selected := p.sel.Select(r.IsReverse, r.Language, phonemized, r.Languages)
+		parts_of_speech_selected := p.sel.Select(r.IsReverse, r.Language, phonemized, r.Languages, collapse(hints_all), r.Disable)
 		log.Now().Debugf("Vector: %v", parts_of_speech_selected)
 
+		if !r.Disable.CrossWord {
+			fired[j] = p.cross.Apply(r.IsReverse, r.Language, parts_of_speech_selected, punctuation[j])
+		}
+
 		if totalLenSplitted.Load() > p.maxwrds {
 			return
 		}
 
-		if r.IpaFlavors != nil {
+		if r.Alignment {
+			alignments[j] = make([][][2]string, len(parts_of_speech_selected))
+			for i, word := range parts_of_speech_selected {
+				alignment := p.phon.AlignWord(r.IsReverse, r.Language, strings.TrimRight(word[0], " "), word[1])
+				// the flavor sees the whole word, for its context and for rules spanning chunks
+				var phones = make([]string, len(alignment))
+				for k := range alignment {
+					phones[k] = alignment[k][1]
+				}
+				for _, flavor := range flavors {
+					phones = p.flavor.ApplyParts(flavor, phones)
+				}
+				for k := range alignment {
+					alignment[k][1] = phones[k]
+				}
+				alignments[j][i] = alignment
+			}
+		}
+
+		if flavors != nil {
 			for _, word := range parts_of_speech_selected {
-				for _, flavor := range r.IpaFlavors {
+				for _, flavor := range flavors {
 					word[1] = p.flavor.Apply(flavor, word[1])
 				}
 				ipa_flavored[j] = append(ipa_flavored[j], word)
@@ -104,7 +307,13 @@ func (p *PhonemizeUsecase) Sentence(r requests.PhonemizeSentence) (resp response
 	}
 	resp.Init()
 	for j := range ipa_flavored {
+		if r.PunctuationTokens && len(ipa_flavored[j]) == 0 {
+			p.punctuationTokens(&r, &resp, stray[j])
+		}
 		for i := range ipa_flavored[j] {
+			if r.PunctuationTokens {
+				p.punctuationTokens(&r, &resp, punctuation[j][i][0])
+			}
 			resp.Words = append(resp.Words, responses.PhonemizeSentenceWord{
 				Phonetic:  strings.Trim(ipa_flavored[j][i][1], "_"),
 				CleanWord: strings.TrimRight(ipa_flavored[j][i][0], " "),
@@ -114,28 +323,127 @@ func (p *PhonemizeUsecase) Sentence(r requests.PhonemizeSentence) (resp response
 				IsFirst:   i == 0,
 				IsLast:    i == len(ipa_flavored[j])-1,
 			})
+			if i < len(cleaned[j]) {
+				resp.Words[len(resp.Words)-1].CleanStatus = cleaned[j][i]
+			}
+			if alignments[j] != nil {
+				resp.Words[len(resp.Words)-1].Alignment = alignments[j][i]
+			}
+			if fired[j] != nil {
+				resp.Words[len(resp.Words)-1].CrossWordRules = fired[j][i]
+			}
+			if r.PunctuationTokens {
+				p.punctuationTokens(&r, &resp, punctuation[j][i][1])
+			}
 			//resp.Whole += ipa_flavored[i]
 		}
 	}
+	if r.SymbolTable != "" {
+		p.symbols(r.SymbolTable, &resp)
+	}
 
 	return
 }
 
+// attachLoose glues the punctuation of tokens which have no word, such as a dash between
+// spaces, to the previous word of the sentence, or to the next one when none precedes.
+// It returns the punctuation of a sentence without words, which has nothing to glue to.
+func attachLoose(punct [][2]string, words [][]map[string]uint32, loose []string) string {
+	var k int
+	var pending string
+	for i := range words {
+		if loose[i] != "" {
+			if k > 0 {
+				punct[k-1][1] += loose[i]
+			} else {
+				pending += loose[i]
+			}
+		}
+		if len(words[i]) > 0 && pending != "" {
+			punct[k][0] = pending + punct[k][0]
+			pending = ""
+		}
+		k += len(words[i])
+	}
+	return pending
+}
+
+// punctuationTokens appends the punctuation marks as tokens of their own.
+func (p *PhonemizeUsecase) punctuationTokens(r *requests.PhonemizeSentence, resp *responses.PhonemizeSentence, punct string) {
+	for _, token := range p.punct.Tokens(r.IsReverse, r.Language, punct) {
+		var kind = token.Punctuation
+		resp.Words = append(resp.Words, responses.PhonemizeSentenceWord{
+			CleanWord:   token.Text,
+			PosTags:     json.RawMessage("[]"),
+			Punctuation: &kind,
+		})
+	}
+}
+
+// symbols fills in the phoneme IDs of the words using the named symbol table.
+func (p *PhonemizeUsecase) symbols(name string, resp *responses.PhonemizeSentence) {
+	var phonetic []string
+	var punct [][2]string
+	var index []int
+	for i, word := range resp.Words {
+		if word.Punctuation != nil {
+			// the marks are encoded with the punctuation of the words
+			continue
+		}
+		phonetic = append(phonetic, word.Phonetic)
+		punct = append(punct, [2]string{word.PrePunct, word.PostPunct})
+		index = append(index, i)
+	}
+	words, ids, ok := p.sym.Encode(name, phonetic, punct)
+	if !ok {
+		resp.ErrorUnknownSymbolTable = true
+		return
+	}
+	resp.PhonemeIds = ids
+	for n, word := range words {
+		i := index[n]
+		resp.Words[i].PhoneTokens = word.Tokens
+		resp.Words[i].PhoneIds = word.Ids
+		for _, missing := range word.Missing {
+			resp.Words[i].MissingPhones = append(resp.Words[i].MissingPhones, responses.MissingPhone{
+				Phone:      missing.Phone,
+				Suggestion: missing.Suggestion,
+			})
+		}
+	}
+}
+
 func NewPhonemizeUsecase(di *DependencyInjection) *PhonemizeUsecase {
 	service := MustNeed(di, services.NewSplitWordsService)
 	phon := MustNeed(di, services.NewPhonemizeWordService)
 	sel := MustNeed(di, services.NewPartsOfSpeechSelectorService)
+	cross := MustNeed(di, services.NewCrossWordService)
+	punct := MustNeed(di, services.NewPunctuationService)
 	flavor := MustNeed(di, services.NewIpaFlavorService)
 	sent := MustNeed(di, services.NewSentencizerService)
+	sym := MustNeed(di, services.NewSymbolTableService)
+	assess := MustNeed(di, services.NewPronunciationAssessmentService)
+	lang := MustNeed(di, services.NewLanguageService)
 	policyMaxWords := MustAny[interfaces.PolicyMaxWords](di)
+	var defaultFlavors map[string][]string
+	var defaults interfaces.DefaultIpaFlavors
+	if Any(di, &defaults) == nil {
+		defaultFlavors = defaults.GetDefaultIpaFlavors()
+	}
 
 	return &PhonemizeUsecase{
 		service: &service,
 		phon:    &phon,
 		sel:     &sel,
+		cross:   &cross,
+		punct:   &punct,
 		flavor:  &flavor,
 		sent:    &sent,
+		sym:     &sym,
+		assess:  &assess,
+		lang:    &lang,
 		maxwrds: uint64(policyMaxWords.GetPolicyMaxWords()),
+		flavors: &defaultFlavors,
 	}
 }
 
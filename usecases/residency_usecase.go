@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"github.com/neurlang/goruut/models/responses"
+	"github.com/neurlang/goruut/pkg/residency"
+	"github.com/neurlang/goruut/repo/interfaces"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type IResidencyUsecase interface {
+	Report() responses.Residency
+}
+
+type ResidencyUsecase struct {
+	res interfaces.Residency
+}
+
+// Report returns the loaded languages with their approximate memory, the loads and the evictions.
+func (r *ResidencyUsecase) Report() (resp responses.Residency) {
+	if r.res == nil {
+		resp.Languages = []residency.Language{}
+		return
+	}
+	resp.Report = r.res.Report()
+	resp.Enabled = true
+	return
+}
+
+func NewResidencyUsecase(di *DependencyInjection) *ResidencyUsecase {
+	var res interfaces.Residency
+	Any(di, &res)
+
+	return &ResidencyUsecase{
+		res: res,
+	}
+}
+
+var _ IResidencyUsecase = &ResidencyUsecase{}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"github.com/neurlang/goruut/models/requests"
+	"github.com/neurlang/goruut/models/responses"
+	"github.com/neurlang/goruut/repo/services"
+)
+import . "github.com/martinarisk/di/dependency_injection"
+
+type ISymbolTableUsecase interface {
+	Store(requests.SymbolTable) responses.SymbolTable
+}
+
+type SymbolTableUsecase struct {
+	sym services.ISymbolTableService
+}
+
+// Store saves the named symbol table, a request without a table removes it.
+// An empty name only lists the stored tables.
+func (s *SymbolTableUsecase) Store(r requests.SymbolTable) (resp responses.SymbolTable) {
+	if r.Name != "" {
+		if err := s.sym.Store(r.Name, r.Table); err != nil {
+			resp.Error = err.Error()
+		}
+	}
+	resp.Names = s.sym.Names()
+	if resp.Names == nil {
+		resp.Names = []string{}
+	}
+	return
+}
+
+func NewSymbolTableUsecase(di *DependencyInjection) *SymbolTableUsecase {
+	sym := MustNeed(di, services.NewSymbolTableService)
+
+	return &SymbolTableUsecase{
+		sym: &sym,
+	}
+}
+
+var _ ISymbolTableUsecase = &SymbolTableUsecase{}